@@ -13,6 +13,10 @@ import (
 // @in header
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description Personal API key from /api/api-keys.
 func main() {
 	app.NewApp().Run()
 }
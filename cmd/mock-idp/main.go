@@ -0,0 +1,34 @@
+// Command mock-idp runs a local OpenID provider for trying the SSO login
+// without a corporate identity provider.
+package main
+
+import (
+	"net/http"
+	"news-api/pkg/logger"
+	"news-api/pkg/oidc/mockidp"
+	"os"
+)
+
+func main() {
+	logger.InitLogger("info")
+
+	addr := getEnv("MOCK_IDP_ADDR", ":9000")
+	issuer := getEnv("MOCK_IDP_ISSUER", "http://localhost:9000")
+
+	idp, err := mockidp.New(issuer, getEnv("OIDC_CLIENT_ID", "news-api"), getEnv("OIDC_CLIENT_SECRET", "news-api-secret"))
+	if err != nil {
+		panic("Failed to start mock IdP: " + err.Error())
+	}
+
+	logger.Log.Info("Mock IdP started", "addr", addr, "issuer", issuer)
+	if err := http.ListenAndServe(addr, idp); err != nil {
+		logger.Log.Error("Mock IdP stopped", "error", err)
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,29 @@
+// Command mock-s3 runs an in-memory S3-compatible service for trying the S3
+// storage driver without a real bucket.
+package main
+
+import (
+	"net/http"
+	"news-api/pkg/logger"
+	"news-api/pkg/storage/s3mock"
+	"os"
+)
+
+func main() {
+	logger.InitLogger("info")
+
+	addr := getEnv("MOCK_S3_ADDR", ":9001")
+	s3 := s3mock.New(getEnv("S3_ACCESS_KEY", "news-api"), getEnv("S3_SECRET_KEY", "news-api-secret"))
+
+	logger.Log.Info("Mock S3 started", "addr", addr)
+	if err := http.ListenAndServe(addr, s3); err != nil {
+		logger.Log.Error("Mock S3 stopped", "error", err)
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return defaultValue
+}
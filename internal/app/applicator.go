@@ -2,7 +2,10 @@ package app
 
 import (
 	"context"
+	"crypto/aes"
+	"crypto/cipher"
 	"database/sql"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/http"
@@ -10,29 +13,73 @@ import (
 	"news-api/internal/database"
 	"news-api/internal/http/handlers"
 	"news-api/internal/http/router"
+	"news-api/internal/models"
 	"news-api/internal/repository"
 	"news-api/internal/service"
 	"news-api/pkg/logger"
+	"news-api/pkg/mailer"
+	"news-api/pkg/oidc"
+	"news-api/pkg/password"
 	redisClient "news-api/pkg/redis"
+	"news-api/pkg/storage"
 	"news-api/pkg/token"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	keySyncInterval         = time.Minute
+	accountDeletionInterval = time.Hour
+	newsScheduleInterval    = 15 * time.Second
+	newsTrashPurgeInterval  = time.Hour
+
+	// keyPublishLead is how long a new signing key is published before it
+	// signs: a sync of every replica plus the 5 minutes JWKS responses may be
+	// cached.
+	keyPublishLead = 10 * time.Minute
+)
+
 type App struct {
-	DB          *sql.DB
-	AuthRepo    *repository.UserRepository
-	AuthService *service.AuthService
-	AuthHandler *handlers.AuthHandler
-	NewsRepo    *repository.NewsRepository
-	NewsService *service.NewsService
-	NewsHandler *handlers.NewsHandler
-	JWTManager  *token.JWTManager
-	RedisClient *redis.Client
-	server      *http.Server
+	DB                   *sql.DB
+	AuthRepo             *repository.UserRepository
+	SessionRepo          *repository.SessionRepository
+	Denylist             *repository.TokenDenylistRepository
+	AuthService          *service.AuthService
+	AuthHandler          *handlers.AuthHandler
+	PasswordHandler      *handlers.PasswordHandler
+	VerificationHandler  *handlers.VerificationHandler
+	ProfileHandler       *handlers.ProfileHandler
+	AvatarHandler        *handlers.AvatarHandler
+	MFAHandler           *handlers.MFAHandler
+	OIDCHandler          *handlers.OIDCHandler
+	MagicLinkHandler     *handlers.MagicLinkHandler
+	APIKeyService        *service.APIKeyService
+	APIKeyHandler        *handlers.APIKeyHandler
+	Authorizer           *service.Authorizer
+	RoleHandler          *handlers.RoleHandler
+	UserAdminHandler     *handlers.UserAdminHandler
+	ImpersonationHandler *handlers.ImpersonationHandler
+	AuditHandler         *handlers.AuditHandler
+	AccountDeleter       *service.AccountDeleter
+	NewsRepo             *repository.NewsRepository
+	NewsService          *service.NewsService
+	NewsScheduler        *service.NewsScheduler
+	NewsTrashPurger      *service.NewsTrashPurger
+	NewsHandler          *handlers.NewsHandler
+	TaxonomyHandler      *handlers.TaxonomyHandler
+	JWTManager           *token.JWTManager
+	KeyRotator           *token.KeyRotator
+	JWKSHandler          *handlers.JWKSHandler
+	MediaHandler         http.Handler
+	RedisClient          *redis.Client
+	server               *http.Server
+	stopWorkers          context.CancelFunc
+	workers              sync.WaitGroup
 }
 
 func NewApp() *App {
@@ -52,33 +99,299 @@ func NewApp() *App {
 		panic("Failed to initialize Redis")
 	}
 
-	jwtManager := token.NewJWTManager(cfg.JWT.Secret, cfg.JWT.ExpirationHours)
+	jwtManager, keyRotator := newJWTManager(cfg.JWT, client)
+	jwksHandler := handlers.NewJWKSHandler(jwtManager)
+
+	mail, err := mailer.New(cfg.Mail)
+	if err != nil {
+		panic("Failed to initialize mailer: " + err.Error())
+	}
+
+	store, err := storage.New(cfg.Storage)
+	if err != nil {
+		panic("Failed to initialize storage: " + err.Error())
+	}
+	var mediaHandler http.Handler
+	if local, ok := store.(*storage.LocalStore); ok {
+		mediaHandler = local.Handler()
+	}
 
 	authRepo := repository.NewUserRepository(database.DB)
-	authService := service.NewAuthService(authRepo, client, jwtManager)
-	authHandler := handlers.NewAuthHandler(authService)
+	verificationRepo := repository.NewEmailVerificationRepository(database.DB)
+	verificationService := service.NewVerificationService(
+		authRepo, verificationRepo, mail, cfg.Server.FrontendURL,
+		time.Duration(cfg.Auth.EmailVerificationTTLHours)*time.Hour,
+	)
+	verificationHandler := handlers.NewVerificationHandler(verificationService)
 
+	sessionRepo := repository.NewSessionRepository(client)
+	denylist := repository.NewTokenDenylistRepository(client)
+	loginGuard := service.NewLoginGuard(repository.NewLoginAttemptRepository(client), service.LoginPolicy{
+		Window:         time.Duration(cfg.Auth.LoginFailureWindowMinutes) * time.Minute,
+		BackoffAfter:   int64(cfg.Auth.LoginBackoffAfter),
+		BackoffBase:    time.Duration(cfg.Auth.LoginBackoffBaseSeconds) * time.Second,
+		LockAfter:      int64(cfg.Auth.LoginLockAfter),
+		LockDuration:   time.Duration(cfg.Auth.LoginLockMinutes) * time.Minute,
+		IPBackoffAfter: int64(cfg.Auth.LoginIPBackoffAfter),
+	})
+	roleRepo := repository.NewRoleRepository(database.DB)
+	authorizer := service.NewAuthorizer(roleRepo)
+	mfaService := service.NewMFAService(
+		authRepo, repository.NewRecoveryCodeRepository(database.DB), repository.NewMFAChallengeRepository(client),
+		authorizer, cfg.Auth.MFAIssuer, cfg.Auth.MFARequiredForAdmin,
+	)
+	mfaHandler := handlers.NewMFAHandler(mfaService)
+
+	auditRepo := repository.NewAuditRepository(database.DB)
+	auditLog := service.NewAuditLog(auditRepo)
+
+	authService := service.NewAuthService(
+		authRepo, sessionRepo, denylist, verificationService, loginGuard, mfaService, authorizer, jwtManager,
+		newPasswordPolicy(cfg.Auth), auditLog,
+	)
+	authHandler := handlers.NewAuthHandler(authService)
 	newsRepo := repository.NewNewsRepository(database.DB)
-	newsService := service.NewNewsService(newsRepo)
+	profileHandler := handlers.NewProfileHandler(service.NewProfileService(
+		authRepo, newsRepo, verificationService, loginGuard, authService, authorizer,
+		time.Duration(cfg.Auth.AccountDeletionGraceDays)*24*time.Hour,
+	))
+	avatarMaxBytes := cfg.Storage.AvatarMaxSizeKB << 10
+	avatarService := service.NewAvatarService(authRepo, store, avatarMaxBytes)
+	avatarHandler := handlers.NewAvatarHandler(avatarService, int64(avatarMaxBytes))
+
+	switch {
+	case cfg.Auth.AccountDeletionNews == models.NewsAnonymize:
+	case cfg.Auth.AccountDeletionNews == models.NewsTransfer && cfg.Auth.AccountDeletionTransferTo > 0:
+	default:
+		panic("ACCOUNT_DELETION_NEWS must be anonymize, or transfer with ACCOUNT_DELETION_TRANSFER_TO set")
+	}
+	accountDeleter := service.NewAccountDeleter(
+		authRepo, authService, avatarService, auditLog, cfg.Auth.AccountDeletionNews, cfg.Auth.AccountDeletionTransferTo,
+	)
+
+	oidcHandler := newOIDCHandler(cfg.OIDC, authRepo, client, authService, verificationService)
+
+	var magicLinkHandler *handlers.MagicLinkHandler
+	if cfg.Auth.MagicLinkEnabled {
+		magicLinkHandler = handlers.NewMagicLinkHandler(service.NewMagicLinkService(
+			authRepo, repository.NewMagicLinkRepository(client), authService, jwtManager, mail, cfg.Server.FrontendURL,
+			time.Duration(cfg.Auth.MagicLinkTTLMinutes)*time.Minute,
+		))
+	}
+
+	apiKeyService := service.NewAPIKeyService(repository.NewAPIKeyRepository(database.DB), authRepo)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+
+	roleHandler := handlers.NewRoleHandler(service.NewRoleService(roleRepo, authRepo, authorizer, authService, auditLog))
+	userAdminHandler := handlers.NewUserAdminHandler(service.NewUserAdminService(authRepo, authorizer, authService, accountDeleter, auditLog))
+	auditHandler := handlers.NewAuditHandler(service.NewAuditService(auditRepo, authorizer))
+
+	impersonationHandler := handlers.NewImpersonationHandler(service.NewImpersonationService(
+		authRepo, repository.NewImpersonationRepository(database.DB), authorizer, jwtManager,
+		time.Duration(cfg.Auth.ImpersonationTTLMinutes)*time.Minute, auditLog,
+	))
+
+	resetRepo := repository.NewPasswordResetRepository(database.DB)
+	passwordService := service.NewPasswordService(
+		authRepo, resetRepo, authService, mail, cfg.Server.FrontendURL,
+		time.Duration(cfg.Auth.PasswordResetTTLMinutes)*time.Minute,
+	)
+	passwordHandler := handlers.NewPasswordHandler(passwordService)
+
+	taxonomyRepo := repository.NewTaxonomyRepository(database.DB)
+	newsService := service.NewNewsService(newsRepo, taxonomyRepo, authorizer, auditLog)
 	newsHandler := handlers.NewNewsHandler(newsService)
+	taxonomyHandler := handlers.NewTaxonomyHandler(service.NewTaxonomyService(taxonomyRepo, authorizer, auditLog))
+
+	var newsTrashPurger *service.NewsTrashPurger
+	if cfg.News.TrashRetentionDays > 0 {
+		newsTrashPurger = service.NewNewsTrashPurger(
+			newsRepo, auditLog, time.Duration(cfg.News.TrashRetentionDays)*24*time.Hour,
+		)
+	}
 
 	return &App{
-		DB:          database.DB,
-		AuthRepo:    authRepo,
-		AuthService: authService,
-		AuthHandler: authHandler,
-		NewsRepo:    newsRepo,
-		NewsService: newsService,
-		NewsHandler: newsHandler,
-		JWTManager:  jwtManager,
-		RedisClient: client,
+		DB:                   database.DB,
+		AuthRepo:             authRepo,
+		SessionRepo:          sessionRepo,
+		Denylist:             denylist,
+		AuthService:          authService,
+		AuthHandler:          authHandler,
+		PasswordHandler:      passwordHandler,
+		VerificationHandler:  verificationHandler,
+		ProfileHandler:       profileHandler,
+		AvatarHandler:        avatarHandler,
+		MFAHandler:           mfaHandler,
+		OIDCHandler:          oidcHandler,
+		MagicLinkHandler:     magicLinkHandler,
+		APIKeyService:        apiKeyService,
+		APIKeyHandler:        apiKeyHandler,
+		Authorizer:           authorizer,
+		RoleHandler:          roleHandler,
+		UserAdminHandler:     userAdminHandler,
+		ImpersonationHandler: impersonationHandler,
+		AuditHandler:         auditHandler,
+		AccountDeleter:       accountDeleter,
+		NewsRepo:             newsRepo,
+		NewsService:          newsService,
+		NewsScheduler:        service.NewNewsScheduler(newsRepo, auditLog),
+		NewsTrashPurger:      newsTrashPurger,
+		NewsHandler:          newsHandler,
+		TaxonomyHandler:      taxonomyHandler,
+		JWTManager:           jwtManager,
+		KeyRotator:           keyRotator,
+		JWKSHandler:          jwksHandler,
+		MediaHandler:         mediaHandler,
+		RedisClient:          client,
+	}
+}
+
+// newJWTManager returns an HS256 manager for the shared secret, or a key ring
+// manager whose keys are shared through Redis and rotated by the returned
+// KeyRotator.
+func newJWTManager(cfg config.JWTConfig, client *redis.Client) (*token.JWTManager, *token.KeyRotator) {
+	switch cfg.Algorithm {
+	case token.AlgHS256:
+		return token.NewJWTManager(cfg.Secret, cfg.ExpirationHours), nil
+	case token.AlgRS256, token.AlgEdDSA:
+	default:
+		panic("Unsupported JWT_ALGORITHM: " + cfg.Algorithm)
 	}
+
+	ring := token.NewKeyRing()
+	rotator := token.NewKeyRotator(
+		repository.NewSigningKeyRepository(client, newKeyCipher(cfg.KeyEncryptionKey)), ring, cfg.Algorithm,
+		time.Duration(cfg.KeyRotationHours)*time.Hour, keyPublishLead, token.RefreshTokenTTL,
+	)
+
+	// Another replica may hold the rotation lock while generating the first key.
+	for i := 0; i < 30; i++ {
+		err := rotator.Sync(context.Background())
+		if err == nil && ring.Current() != nil {
+			break
+		}
+		if errors.Is(err, token.ErrKeyUnreadable) {
+			panic("Failed to read JWT signing keys: " + err.Error())
+		}
+		if err != nil {
+			logger.Log.Warn("Signing keys not ready, retrying in 1s...", "error", err)
+		}
+		time.Sleep(1 * time.Second)
+	}
+	if ring.Current() == nil {
+		panic("Failed to load JWT signing keys")
+	}
+
+	logger.Log.Info("JWT key ring initialized", "alg", cfg.Algorithm, "kid", ring.Current().ID)
+	return token.NewKeyRingJWTManager(ring, cfg.ExpirationHours), rotator
+}
+
+func newKeyCipher(encoded string) cipher.AEAD {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != 32 {
+		panic("JWT_KEY_ENCRYPTION_KEY must be 32 bytes in base64 when JWT_ALGORITHM is RS256 or EdDSA")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		panic("Invalid JWT_KEY_ENCRYPTION_KEY: " + err.Error())
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic("Invalid JWT_KEY_ENCRYPTION_KEY: " + err.Error())
+	}
+	return aead
+}
+
+// newPasswordPolicy also sets how password hashes are made.
+func newPasswordPolicy(cfg config.AuthConfig) *password.Policy {
+	err := password.Configure(password.Params{
+		Algorithm:   cfg.PasswordHashAlgorithm,
+		Memory:      uint32(cfg.Argon2MemoryKB),
+		Iterations:  uint32(cfg.Argon2Iterations),
+		Parallelism: uint8(cfg.Argon2Parallelism),
+		BcryptCost:  cfg.BcryptCost,
+	})
+	if err != nil {
+		panic("Invalid password hashing settings: " + err.Error())
+	}
+
+	policy := &password.Policy{
+		MinLength:  cfg.PasswordMinLength,
+		MaxLength:  cfg.PasswordMaxLength,
+		MinClasses: cfg.PasswordMinClasses,
+	}
+	if cfg.BreachedPasswordsPath != "" {
+		list, err := password.LoadBreachedList(cfg.BreachedPasswordsPath)
+		if err != nil {
+			panic("Failed to load breached passwords: " + err.Error())
+		}
+		policy.Breached = list
+		logger.Log.Info("Breached password check enabled", "path", cfg.BreachedPasswordsPath)
+	}
+	return policy
+}
+
+// newOIDCHandler returns nil when SSO is not configured, which leaves the
+// SSO routes unregistered.
+func newOIDCHandler(
+	cfg config.OIDCConfig,
+	userRepo *repository.UserRepository,
+	client *redis.Client,
+	authService *service.AuthService,
+	verifier *service.VerificationService,
+) *handlers.OIDCHandler {
+	if cfg.Issuer == "" {
+		return nil
+	}
+
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:       cfg.Issuer,
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURL,
+		Scopes:       strings.Fields(cfg.Scopes),
+		GroupsClaim:  cfg.GroupsClaim,
+	})
+	oidcService := service.NewOIDCService(
+		provider, cfg.Issuer, userRepo,
+		repository.NewUserIdentityRepository(database.DB), repository.NewOIDCStateRepository(client),
+		authService, verifier,
+		service.OIDCRoleMapping{AdminGroups: splitList(cfg.AdminGroups), EditorGroups: splitList(cfg.EditorGroups)},
+	)
+
+	logger.Log.Info("OIDC login enabled", "issuer", cfg.Issuer)
+	return handlers.NewOIDCHandler(oidcService)
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
 }
 
 func (a *App) Run() {
 	cfg := config.LoadConfig()
 
-	routers := router.NewRouter(a.AuthHandler, a.NewsHandler, a.JWTManager)
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	a.stopWorkers = stopWorkers
+	if a.KeyRotator != nil {
+		a.startWorker(func() { a.KeyRotator.Run(workersCtx, keySyncInterval) })
+	}
+	a.startWorker(func() { a.AccountDeleter.Run(workersCtx, accountDeletionInterval) })
+	a.startWorker(func() { a.NewsScheduler.Run(workersCtx, newsScheduleInterval) })
+	if a.NewsTrashPurger != nil {
+		a.startWorker(func() { a.NewsTrashPurger.Run(workersCtx, newsTrashPurgeInterval) })
+	}
+
+	routers := router.NewRouter(
+		a.AuthHandler, a.PasswordHandler, a.VerificationHandler, a.ProfileHandler, a.AvatarHandler, a.MFAHandler,
+		a.OIDCHandler, a.MagicLinkHandler, a.APIKeyHandler, a.RoleHandler, a.UserAdminHandler, a.ImpersonationHandler,
+		a.AuditHandler, a.NewsHandler, a.TaxonomyHandler, a.JWKSHandler, a.MediaHandler, a.JWTManager, a.Denylist, a.APIKeyService, a.Authorizer,
+	)
 	a.server = &http.Server{
 		Addr:    ":" + cfg.Server.Port,
 		Handler: routers,
@@ -103,11 +416,25 @@ func (a *App) Run() {
 	}
 }
 
+// startWorker runs a background worker that Shutdown waits for before it
+// closes the connections the worker uses.
+func (a *App) startWorker(run func()) {
+	a.workers.Add(1)
+	go func() {
+		defer a.workers.Done()
+		run()
+	}()
+}
+
 func (a *App) Shutdown(ctx context.Context) error {
 	logger.Log.Info("Shutting down server...")
 
 	var errList []error
 
+	if a.stopWorkers != nil {
+		a.stopWorkers()
+	}
+
 	if a.server != nil {
 		if err := a.server.Shutdown(ctx); err != nil {
 			logger.Log.Error("Error shutting down HTTP server", "error", err)
@@ -115,6 +442,18 @@ func (a *App) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	workersDone := make(chan struct{})
+	go func() {
+		a.workers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+	case <-ctx.Done():
+		logger.Log.Error("Background workers did not stop in time", "error", ctx.Err())
+		errList = append(errList, ctx.Err())
+	}
+
 	if a.RedisClient != nil {
 		if err := a.RedisClient.Close(); err != nil {
 			logger.Log.Error("Error closing Redis", "error", err)
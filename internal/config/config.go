@@ -20,6 +20,99 @@ type Config struct {
 	JWT      JWTConfig
 	Log      LogConfig
 	Redis    RedisConfig
+	Mail     MailConfig
+	Auth     AuthConfig
+	OIDC     OIDCConfig
+	Storage  StorageConfig
+	News     NewsConfig
+}
+
+type MailConfig struct {
+	Driver       string
+	From         string
+	Dir          string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+}
+
+type AuthConfig struct {
+	PasswordResetTTLMinutes   int
+	EmailVerificationTTLHours int
+
+	// Password policy for new passwords. BreachedPasswordsPath points to a
+	// HIBP-style range directory or hash file; empty turns the check off.
+	PasswordMinLength     int
+	PasswordMaxLength     int
+	PasswordMinClasses    int
+	BreachedPasswordsPath string
+
+	// How new password hashes are made: "argon2id" or "bcrypt". Hashes with
+	// other settings are upgraded on the next successful login.
+	PasswordHashAlgorithm string
+	Argon2MemoryKB        int
+	Argon2Iterations      int
+	Argon2Parallelism     int
+	BcryptCost            int
+
+	LoginFailureWindowMinutes int
+	LoginBackoffAfter         int
+	LoginBackoffBaseSeconds   int
+	LoginLockAfter            int
+	LoginLockMinutes          int
+	LoginIPBackoffAfter       int
+
+	MFAIssuer           string
+	MFARequiredForAdmin bool
+
+	// Self-service account deletion: the grace period and what happens to
+	// the news of the user afterwards ("anonymize" or "transfer" to the
+	// user AccountDeletionTransferTo).
+	AccountDeletionGraceDays  int
+	AccountDeletionNews       string
+	AccountDeletionTransferTo int
+
+	// Lifetime of the access token an admin gets to act as another user.
+	ImpersonationTTLMinutes int
+
+	// Passwordless login through emailed links.
+	MagicLinkEnabled    bool
+	MagicLinkTTLMinutes int
+}
+
+// OIDCConfig configures SSO through an OpenID provider. SSO is off while
+// Issuer is empty. Groups lists are comma separated.
+type OIDCConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       string
+	GroupsClaim  string
+	AdminGroups  string
+	EditorGroups string
+}
+
+// StorageConfig selects where uploaded files are kept. PublicURL defaults to
+// BaseURL/media for the local driver and to endpoint/bucket for S3.
+type StorageConfig struct {
+	Driver      string
+	Dir         string
+	PublicURL   string
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+
+	AvatarMaxSizeKB int
+}
+
+// NewsConfig holds settings of the news lifecycle. Deleted news is purged
+// after TrashRetentionDays; zero or less keeps it in the trash forever.
+type NewsConfig struct {
+	TrashRetentionDays int
 }
 
 type LogConfig struct {
@@ -27,7 +120,11 @@ type LogConfig struct {
 }
 
 type ServerConfig struct {
-	Port string
+	Port    string
+	BaseURL string
+	// FrontendURL is where the pages opened from links in emails live; the
+	// API itself only accepts their tokens by POST.
+	FrontendURL string
 }
 
 type DatabaseConfig struct {
@@ -40,8 +137,13 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-	Secret          string
-	ExpirationHours int
+	Secret           string
+	ExpirationHours  int
+	Algorithm        string
+	KeyRotationHours int
+	// KeyEncryptionKey is a base64 encoded 32-byte key that encrypts the
+	// RS256/EdDSA private keys kept in Redis.
+	KeyEncryptionKey string
 }
 
 func LoadConfig() Config {
@@ -49,7 +151,9 @@ func LoadConfig() Config {
 
 	cfg := Config{
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8080"),
+			Port:        getEnv("SERVER_PORT", "8080"),
+			BaseURL:     getEnv("APP_BASE_URL", "http://localhost:8080"),
+			FrontendURL: getEnv("APP_FRONTEND_URL", "http://localhost:3000"),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -60,8 +164,11 @@ func LoadConfig() Config {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		JWT: JWTConfig{
-			Secret:          getEnv("JWT_SECRET", "secretkey"),
-			ExpirationHours: getEnvInt("JWT_EXPIRATION_HOURS", 1),
+			Secret:           getEnv("JWT_SECRET", "secretkey"),
+			ExpirationHours:  getEnvInt("JWT_EXPIRATION_HOURS", 1),
+			Algorithm:        getEnv("JWT_ALGORITHM", "HS256"),
+			KeyRotationHours: getEnvInt("JWT_KEY_ROTATION_HOURS", 720),
+			KeyEncryptionKey: getEnv("JWT_KEY_ENCRYPTION_KEY", ""),
 		},
 		Log: LogConfig{
 			Level: getEnv("LOG_LEVEL", "info"),
@@ -71,6 +178,81 @@ func LoadConfig() Config {
 			Username: getEnv("REDIS_USERNAME", "default"),
 			Password: getEnv("REDIS_PASSWORD", ""),
 		},
+		Mail: MailConfig{
+			Driver:       getEnv("MAIL_DRIVER", "log"),
+			From:         getEnv("MAIL_FROM", "News API <no-reply@localhost>"),
+			Dir:          getEnv("MAIL_DIR", "mail"),
+			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		},
+		Auth: AuthConfig{
+			PasswordResetTTLMinutes:   getEnvInt("PASSWORD_RESET_TTL_MINUTES", 60),
+			EmailVerificationTTLHours: getEnvInt("EMAIL_VERIFICATION_TTL_HOURS", 48),
+
+			PasswordMinLength:     getEnvInt("PASSWORD_MIN_LENGTH", 8),
+			PasswordMaxLength:     getEnvInt("PASSWORD_MAX_LENGTH", 72),
+			PasswordMinClasses:    getEnvInt("PASSWORD_MIN_CLASSES", 2),
+			BreachedPasswordsPath: getEnv("BREACHED_PASSWORDS_PATH", ""),
+
+			PasswordHashAlgorithm: getEnv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+			Argon2MemoryKB:        getEnvInt("ARGON2_MEMORY_KB", 65536),
+			Argon2Iterations:      getEnvInt("ARGON2_ITERATIONS", 3),
+			Argon2Parallelism:     getEnvInt("ARGON2_PARALLELISM", 4),
+			BcryptCost:            getEnvInt("BCRYPT_COST", 10),
+
+			LoginFailureWindowMinutes: getEnvInt("LOGIN_FAILURE_WINDOW_MINUTES", 15),
+			LoginBackoffAfter:         getEnvInt("LOGIN_BACKOFF_AFTER", 3),
+			LoginBackoffBaseSeconds:   getEnvInt("LOGIN_BACKOFF_BASE_SECONDS", 1),
+			LoginLockAfter:            getEnvInt("LOGIN_LOCK_AFTER", 10),
+			LoginLockMinutes:          getEnvInt("LOGIN_LOCK_MINUTES", 15),
+			LoginIPBackoffAfter:       getEnvInt("LOGIN_IP_BACKOFF_AFTER", 50),
+
+			MFAIssuer:           getEnv("MFA_ISSUER", "News API"),
+			MFARequiredForAdmin: getEnvBool("MFA_REQUIRED_FOR_ADMIN", false),
+
+			AccountDeletionGraceDays:  getEnvInt("ACCOUNT_DELETION_GRACE_DAYS", 14),
+			AccountDeletionNews:       getEnv("ACCOUNT_DELETION_NEWS", "anonymize"),
+			AccountDeletionTransferTo: getEnvInt("ACCOUNT_DELETION_TRANSFER_TO", 0),
+
+			ImpersonationTTLMinutes: getEnvInt("IMPERSONATION_TTL_MINUTES", 15),
+
+			MagicLinkEnabled:    getEnvBool("MAGIC_LINK_ENABLED", false),
+			MagicLinkTTLMinutes: getEnvInt("MAGIC_LINK_TTL_MINUTES", 15),
+		},
+		OIDC: OIDCConfig{
+			Issuer:       getEnv("OIDC_ISSUER", ""),
+			ClientID:     getEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+			RedirectURL:  getEnv("OIDC_REDIRECT_URL", ""),
+			Scopes:       getEnv("OIDC_SCOPES", "openid email profile"),
+			GroupsClaim:  getEnv("OIDC_GROUPS_CLAIM", "groups"),
+			AdminGroups:  getEnv("OIDC_ADMIN_GROUPS", ""),
+			EditorGroups: getEnv("OIDC_EDITOR_GROUPS", ""),
+		},
+		Storage: StorageConfig{
+			Driver:      getEnv("STORAGE_DRIVER", "local"),
+			Dir:         getEnv("STORAGE_DIR", "uploads"),
+			PublicURL:   getEnv("STORAGE_PUBLIC_URL", ""),
+			S3Endpoint:  getEnv("S3_ENDPOINT", ""),
+			S3Region:    getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:    getEnv("S3_BUCKET", ""),
+			S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+
+			AvatarMaxSizeKB: getEnvInt("AVATAR_MAX_SIZE_KB", 5120),
+		},
+		News: NewsConfig{
+			TrashRetentionDays: getEnvInt("NEWS_TRASH_RETENTION_DAYS", 30),
+		},
+	}
+
+	if cfg.OIDC.RedirectURL == "" {
+		cfg.OIDC.RedirectURL = cfg.Server.BaseURL + "/api/auth/oidc/callback"
+	}
+	if cfg.Storage.PublicURL == "" && cfg.Storage.Driver == "local" {
+		cfg.Storage.PublicURL = cfg.Server.BaseURL + "/media"
 	}
 
 	return cfg
@@ -94,3 +276,15 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+		slog.Warn("Invalid bool value for env",
+			slog.String("key", key),
+			slog.String("value", value))
+	}
+	return defaultValue
+}
@@ -0,0 +1,26 @@
+package apikey
+
+import "time"
+
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name"            validate:"required,max=100"`
+	Scopes        []string `json:"scopes"          validate:"required" example:"news:create,news:update"`
+	ExpiresInDays int      `json:"expires_in_days" example:"90"`
+}
+
+type APIKeyResponse struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse is returned once on creation; Key cannot be
+// retrieved again later.
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
@@ -0,0 +1,24 @@
+package audit
+
+import "time"
+
+type EventResponse struct {
+	ID             int64     `json:"id"`
+	ActorID        *int      `json:"actor_id"`
+	ImpersonatorID *int      `json:"impersonator_id,omitempty"`
+	Action         string    `json:"action"      example:"auth.login"`
+	TargetType     string    `json:"target_type" example:"user"`
+	TargetID       string    `json:"target_id"   example:"42"`
+	Outcome        string    `json:"outcome"     example:"failure"`
+	Detail         string    `json:"detail"      example:"incorrect email or password (user@example.com)"`
+	IP             string    `json:"ip"`
+	UserAgent      string    `json:"user_agent"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type EventListResponse struct {
+	Items  []EventResponse `json:"items"`
+	Total  int             `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
@@ -4,11 +4,48 @@ type RegisterUserInput struct {
 	FirstName string `json:"first_name" validate:"required"`
 	LastName  string `json:"last_name"  validate:"required"`
 	Email     string `json:"email"      validate:"required,email"`
-	Password  string `json:"password"   validate:"required,min=6"`
-	Avatar    string `json:"avatar,omitempty"`
+	Password  string `json:"password"   validate:"required,min=8"`
 }
 
 type LoginUserInput struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
 }
+
+type RefreshTokenInput struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token"    validate:"required"`
+	Password string `json:"password" validate:"required,min=8"`
+}
+
+type MagicLinkInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type MagicLinkLoginInput struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type VerifyEmailInput struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type MFALoginInput struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code"      validate:"required"`
+}
+
+type MFATokenInput struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+}
+
+type MFACodeInput struct {
+	Code string `json:"code" validate:"required"`
+}
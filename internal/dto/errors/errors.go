@@ -1,6 +1,9 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 type ErrorResponse struct {
 	Code    int    `json:"code"`
@@ -12,4 +15,42 @@ var (
 	ErrForbidden    = errors.New("forbidden")
 	ErrNotFound     = errors.New("not found")
 	ErrValidation   = errors.New("validation error")
+	ErrTokenReused  = errors.New("refresh token reuse detected")
+	ErrInvalidToken = errors.New("invalid or expired token")
+
+	ErrEmailNotVerified = errors.New("email not verified")
+	ErrAlreadyVerified  = errors.New("email already verified")
+
+	ErrTooManyAttempts  = errors.New("too many attempts")
+	ErrAccountLocked    = errors.New("account temporarily locked")
+	ErrAccountSuspended = errors.New("account suspended")
+
+	ErrInvalidMFACode    = errors.New("invalid MFA code")
+	ErrMFAAlreadyEnabled = errors.New("MFA already enabled")
+	ErrMFANotEnabled     = errors.New("MFA not enabled")
+
+	ErrAccountExists = errors.New("account with this email already exists")
+	ErrWrongPassword = errors.New("current password is incorrect")
+	ErrFileTooLarge  = errors.New("file is too large")
+
+	ErrRoleExists = errors.New("role already exists")
+	ErrRoleInUse  = errors.New("role is assigned to users")
+
+	ErrCategoryExists = errors.New("category slug already taken")
+	ErrCategoryInUse  = errors.New("category has subcategories")
+	ErrTagExists      = errors.New("tag already exists")
 )
+
+// RetryAfterError tells the caller when the request may be retried.
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
@@ -1,8 +1,15 @@
 package news
 
+import (
+	"news-api/internal/models"
+	"time"
+)
+
 type News struct {
-	Title       string `json:"title" binding:"required,max=255"`
-	Description string `json:"description" binding:"required"`
+	Title       string   `json:"title" binding:"required,max=255"`
+	Description string   `json:"description" binding:"required"`
+	CategoryIDs []int    `json:"category_ids,omitempty" example:"1,4"`
+	Tags        []string `json:"tags,omitempty" example:"выборы,москва"`
 }
 
 type UpdateNewsRequest struct {
@@ -10,3 +17,20 @@ type UpdateNewsRequest struct {
 	Title       *string `json:"title,omitempty" binding:"max=255"`
 	Description *string `json:"description,omitempty"`
 }
+
+// ChangeStatusRequest moves news to another status. PublishAt is required
+// with "scheduled"; UnpublishAt may be given with "scheduled" and
+// "published".
+type ChangeStatusRequest struct {
+	Status      string     `json:"status" binding:"required" example:"scheduled"`
+	PublishAt   *time.Time `json:"publish_at,omitempty" example:"2026-11-01T09:00:00Z"`
+	UnpublishAt *time.Time `json:"unpublish_at,omitempty" example:"2026-12-01T09:00:00Z"`
+}
+
+// TrashListResponse is a page of deleted news.
+type TrashListResponse struct {
+	Items  []models.News `json:"items"`
+	Total  int           `json:"total"`
+	Limit  int           `json:"limit"`
+	Offset int           `json:"offset"`
+}
@@ -0,0 +1,31 @@
+package role
+
+import "time"
+
+type CreateRoleRequest struct {
+	Name        string   `json:"name"        validate:"required" example:"moderator"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions" example:"news:create,news:update:any"`
+}
+
+type UpdateRoleRequest struct {
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions" example:"news:create,news:update:own"`
+}
+
+type AssignRoleRequest struct {
+	Role string `json:"role" validate:"required" example:"editor"`
+}
+
+type RoleResponse struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	System      bool      `json:"system"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type PermissionResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,13 @@
+package taxonomy
+
+// CategoryRequest creates a category or replaces all of its fields.
+type CategoryRequest struct {
+	Name        string `json:"name"        validate:"required" example:"Политика"`
+	Slug        string `json:"slug"        validate:"required" example:"politics"`
+	Description string `json:"description"`
+	ParentID    *int   `json:"parent_id"   example:"1"`
+}
+
+type TagRequest struct {
+	Name string `json:"name" validate:"required" example:"выборы"`
+}
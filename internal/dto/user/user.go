@@ -0,0 +1,109 @@
+package user
+
+import (
+	"news-api/internal/models"
+	"time"
+)
+
+type UserResponse struct {
+	ID              int        `json:"id"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	Email           string     `json:"email"`
+	Avatar          string     `json:"avatar,omitempty"`
+	Role            string     `json:"role"`
+	EmailVerified   bool       `json:"email_verified"`
+	MFAEnabled      bool       `json:"mfa_enabled"`
+	SuspendedAt     *time.Time `json:"suspended_at,omitempty"`
+	SuspendedReason string     `json:"suspended_reason,omitempty"`
+	// Set while a self-service deletion of the account is pending.
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	// Set on /api/me when an admin is acting as the user.
+	ImpersonatedBy int `json:"impersonated_by,omitempty"`
+}
+
+type UserListResponse struct {
+	Items  []UserResponse `json:"items"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+// UpdateProfileRequest changes only the fields that are present.
+type UpdateProfileRequest struct {
+	FirstName *string `json:"first_name,omitempty" example:"Ivan"`
+	LastName  *string `json:"last_name,omitempty"  example:"Petrov"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password"     validate:"required,min=8"`
+}
+
+type ChangeEmailRequest struct {
+	Email    string `json:"email"    validate:"required,email" example:"new@example.com"`
+	Password string `json:"password" validate:"required"`
+}
+
+type AvatarResponse struct {
+	URL      string            `json:"url"`
+	Variants map[string]string `json:"variants"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+type DeletionScheduledResponse struct {
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+}
+
+// ExportProfile is the account part of a personal data export.
+type ExportProfile struct {
+	ID                  int        `json:"id"`
+	FirstName           string     `json:"first_name"`
+	LastName            string     `json:"last_name"`
+	Email               string     `json:"email"`
+	Role                string     `json:"role"`
+	Avatar              string     `json:"avatar,omitempty"`
+	EmailVerifiedAt     *time.Time `json:"email_verified_at,omitempty"`
+	MFAEnabledAt        *time.Time `json:"mfa_enabled_at,omitempty"`
+	SuspendedAt         *time.Time `json:"suspended_at,omitempty"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// ExportResponse is the JSON form of a personal data export. The ZIP form
+// holds the same parts as profile.json, news.json and sessions.json.
+type ExportResponse struct {
+	ExportedAt time.Time        `json:"exported_at"`
+	Profile    ExportProfile    `json:"profile"`
+	News       []models.News    `json:"news"`
+	Sessions   []models.Session `json:"sessions"`
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason" example:"Spam"`
+}
+
+// DeleteUserInput says what happens to the news of a deleted user: one of
+// models.NewsDelete, NewsTransfer (to TransferTo, by default the admin) or
+// NewsAnonymize.
+type DeleteUserInput struct {
+	News       string
+	TransferTo *int
+}
+
+type ImpersonateRequest struct {
+	Reason string `json:"reason" example:"Ticket #1234: editor cannot see their draft"`
+}
+
+// ImpersonationResponse carries an access token acting as the user. It
+// cannot be refreshed.
+type ImpersonationResponse struct {
+	AccessToken    string    `json:"access_token"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	UserID         int       `json:"user_id"`
+	ImpersonatorID int       `json:"impersonator_id"`
+}
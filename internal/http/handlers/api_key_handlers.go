@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"news-api/internal/dto/apikey"
+	"news-api/internal/dto/auth"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/models"
+	"news-api/internal/service/interfaces"
+	"news-api/pkg/logger"
+	"news-api/utils"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type APIKeyHandler struct {
+	apiKeyService interfaces.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService interfaces.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// Create godoc
+// @Summary      Create API key
+// @Description  Mints a named, scoped, expiring API key for scripts. Send it as the X-API-Key header or as a Bearer token. The key is shown only in this response. Scopes: news:create, news:update, news:delete. expires_in_days defaults to 90, at most 365.
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Param        input  body   apikey.CreateAPIKeyRequest  true  "Key name, scopes and lifetime"
+// @Success      201  {object}  apikey.CreatedAPIKeyResponse
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/api-keys [post]
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var input apikey.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	key, plain, err := h.apiKeyService.Create(r.Context(), actor.UserID, input)
+	if err != nil {
+		if errors.Is(err, errors2.ErrValidation) {
+			utils.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		logger.Log.Error("create API key failed", "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, "failed to create API key")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, apikey.CreatedAPIKeyResponse{
+		APIKeyResponse: apiKeyResponse(*key),
+		Key:            plain,
+	})
+}
+
+// List godoc
+// @Summary      List API keys
+// @Description  Returns the API keys of the current user that are not revoked
+// @Tags         api-keys
+// @Produce      json
+// @Success      200  {array}   apikey.APIKeyResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/api-keys [get]
+func (h *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	keys, err := h.apiKeyService.List(r.Context(), actor.UserID)
+	if err != nil {
+		logger.Log.Error("list API keys failed", "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, "failed to list API keys")
+		return
+	}
+
+	resp := make([]apikey.APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		resp = append(resp, apiKeyResponse(k))
+	}
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+// Revoke godoc
+// @Summary      Revoke API key
+// @Tags         api-keys
+// @Produce      json
+// @Param        id   path      int  true  "API key ID"
+// @Success      200  {object}  auth.Response
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(r.Context(), actor.UserID, id); err != nil {
+		if errors.Is(err, errors2.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, "API key not found")
+			return
+		}
+		logger.Log.Error("revoke API key failed", "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, "failed to revoke API key")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "API key revoked"})
+}
+
+func apiKeyResponse(k models.APIKey) apikey.APIKeyResponse {
+	return apikey.APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
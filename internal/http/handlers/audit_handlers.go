@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"news-api/internal/dto/audit"
+	"news-api/internal/models"
+	"news-api/internal/service/interfaces"
+	"news-api/pkg/logger"
+	"news-api/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AuditHandler struct {
+	auditService interfaces.AuditService
+}
+
+func NewAuditHandler(auditService interfaces.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// ListEvents godoc
+// @Summary      List audit events
+// @Description  Returns security events newest first: logins, logouts, admin actions on users and roles, news changes and refused attempts. format=csv downloads up to 10000 matching events at once, ignoring limit; X-Total-Count then tells how many matched (permission: audit:read)
+// @Tags         admin
+// @Produce      json
+// @Produce      text/csv
+// @Param        limit        query  int     false  "Limit (default 50, max 100)"
+// @Param        offset       query  int     false  "Offset (default 0)"
+// @Param        actor_id     query  int     false  "Filter by acting user"
+// @Param        action       query  string  false  "Filter by action, e.g. auth.login"
+// @Param        target_type  query  string  false  "Filter by target type"  Enums(user, session, role, news)
+// @Param        target_id    query  string  false  "Filter by target id"
+// @Param        outcome      query  string  false  "Filter by outcome"  Enums(success, failure)
+// @Param        from         query  string  false  "Events at or after this time (RFC 3339)"
+// @Param        to           query  string  false  "Events before this time (RFC 3339)"
+// @Param        format       query  string  false  "Response format"  Enums(json, csv)
+// @Success      200  {object}  audit.EventListResponse
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/audit [get]
+func (h *AuditHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	q := r.URL.Query()
+	format := q.Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		utils.WriteError(w, http.StatusBadRequest, "format must be json or csv")
+		return
+	}
+
+	params, err := auditListParams(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if format == "csv" {
+		events, total, err := h.auditService.ExportEvents(r.Context(), actor, params)
+		if err != nil {
+			writeUserAdminError(w, err, "failed to export audit events")
+			return
+		}
+		writeAuditCSV(w, events, total)
+		return
+	}
+
+	events, total, err := h.auditService.ListEvents(r.Context(), actor, params)
+	if err != nil {
+		writeUserAdminError(w, err, "failed to list audit events")
+		return
+	}
+
+	params.Normalize()
+	resp := audit.EventListResponse{
+		Items:  make([]audit.EventResponse, 0, len(events)),
+		Total:  total,
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}
+	for _, e := range events {
+		resp.Items = append(resp.Items, auditEventResponse(e))
+	}
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+func auditListParams(r *http.Request) (models.AuditListParams, error) {
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	params := models.AuditListParams{Limit: limit, Offset: offset}
+
+	if s := q.Get("actor_id"); s != "" {
+		id, err := strconv.Atoi(s)
+		if err != nil || id <= 0 {
+			return params, fmt.Errorf("invalid actor_id")
+		}
+		params.ActorID = &id
+	}
+	if action := q.Get("action"); action != "" {
+		params.Action = &action
+	}
+	if targetType := q.Get("target_type"); targetType != "" {
+		params.TargetType = &targetType
+	}
+	if targetID := q.Get("target_id"); targetID != "" {
+		params.TargetID = &targetID
+	}
+	if outcome := q.Get("outcome"); outcome != "" {
+		if outcome != models.AuditSuccess && outcome != models.AuditFailure {
+			return params, fmt.Errorf("outcome must be success or failure")
+		}
+		params.Outcome = &outcome
+	}
+	for name, dst := range map[string]**time.Time{"from": &params.From, "to": &params.To} {
+		if s := q.Get(name); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return params, fmt.Errorf("invalid %s, expected RFC 3339 time", name)
+			}
+			t = t.UTC()
+			*dst = &t
+		}
+	}
+	return params, nil
+}
+
+func auditEventResponse(e models.AuditEvent) audit.EventResponse {
+	return audit.EventResponse{
+		ID:             e.ID,
+		ActorID:        e.ActorID,
+		ImpersonatorID: e.ImpersonatorID,
+		Action:         e.Action,
+		TargetType:     e.TargetType,
+		TargetID:       e.TargetID,
+		Outcome:        e.Outcome,
+		Detail:         e.Detail,
+		IP:             e.IP,
+		UserAgent:      e.UserAgent,
+		CreatedAt:      e.CreatedAt,
+	}
+}
+
+func writeAuditCSV(w http.ResponseWriter, events []models.AuditEvent, total int) {
+	name := "audit-" + time.Now().UTC().Format("20060102-150405") + ".csv"
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{
+		"id", "created_at", "actor_id", "impersonator_id", "action", "target_type", "target_id",
+		"outcome", "detail", "ip", "user_agent",
+	})
+	for _, e := range events {
+		_ = cw.Write([]string{
+			strconv.FormatInt(e.ID, 10),
+			e.CreatedAt.UTC().Format(time.RFC3339),
+			optionalID(e.ActorID),
+			optionalID(e.ImpersonatorID),
+			e.Action,
+			e.TargetType,
+			csvCell(e.TargetID),
+			e.Outcome,
+			csvCell(e.Detail),
+			csvCell(e.IP),
+			csvCell(e.UserAgent),
+		})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		logger.Log.Error("failed to write audit export", "error", err)
+	}
+}
+
+func optionalID(id *int) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.Itoa(*id)
+}
+
+// csvCell keeps spreadsheets from running user supplied text, such as a
+// crafted email or user agent, as a formula.
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
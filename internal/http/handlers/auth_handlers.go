@@ -2,12 +2,18 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"news-api/internal/dto/auth"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/models"
 	"news-api/internal/service/interfaces"
 	"news-api/pkg/logger"
 	"news-api/utils"
+	"strconv"
+
+	"github.com/gorilla/mux"
 )
 
 type AuthHandler struct {
@@ -47,14 +53,17 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 // Login godoc
 // @Summary      Login user
-// @Description  Authenticate user and return JWT tokens
+// @Description  Authenticate user and return JWT tokens. When MFA is enabled (or mandatory but not yet set up) an MFA challenge token is returned instead.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        input  body   auth.LoginUserInput  true  "Login credentials"
-// @Success      200  {object}  auth.TokensResponse
+// @Success      200  {object}  auth.LoginResponse
 // @Failure      400  {object}  auth.Response
 // @Failure      401  {object}  auth.Response
+// @Failure      403  {object}  auth.Response
+// @Failure      423  {object}  auth.Response
+// @Failure      429  {object}  auth.Response
 // @Router       /api/login [post]
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var input auth.LoginUserInput
@@ -64,10 +73,152 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	accessToken, refreshToken, err := h.authService.Login(r.Context(), input)
-	if err != nil || accessToken == "" {
+	result, err := h.authService.Login(r.Context(), input, utils.ClientInfo(r))
+	if err != nil {
 		logger.Log.Warn("Login failed", slog.String("email", input.Email))
-		utils.WriteJSON(w, http.StatusUnauthorized, auth.Response{Message: "Invalid email or password"})
+		var retry *errors2.RetryAfterError
+		switch {
+		case errors.As(err, &retry) && errors.Is(err, errors2.ErrAccountLocked):
+			utils.SetRetryAfter(w, retry.RetryAfter)
+			utils.WriteJSON(w, http.StatusLocked, auth.Response{Message: "Account is temporarily locked"})
+		case errors.As(err, &retry):
+			utils.SetRetryAfter(w, retry.RetryAfter)
+			utils.WriteJSON(w, http.StatusTooManyRequests, auth.Response{Message: "Too many login attempts"})
+		case errors.Is(err, errors2.ErrAccountSuspended):
+			utils.WriteJSON(w, http.StatusForbidden, auth.Response{Message: "Account is suspended"})
+		default:
+			utils.WriteJSON(w, http.StatusUnauthorized, auth.Response{Message: "Invalid email or password"})
+		}
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, loginResponse(result))
+}
+
+// LoginMFA godoc
+// @Summary      Complete MFA login
+// @Description  Exchange the MFA challenge token from /api/login and a TOTP or recovery code for JWT tokens. For an enrolment challenge the code confirms the new authenticator and recovery codes are returned as well.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input  body   auth.MFALoginInput  true  "MFA challenge token and code"
+// @Success      200  {object}  auth.LoginResponse
+// @Failure      400  {object}  auth.Response
+// @Failure      401  {object}  auth.Response
+// @Failure      403  {object}  auth.Response
+// @Failure      423  {object}  auth.Response
+// @Failure      429  {object}  auth.Response
+// @Failure      500  {object}  errors.ErrorResponse
+// @Router       /api/login/mfa [post]
+func (h *AuthHandler) LoginMFA(w http.ResponseWriter, r *http.Request) {
+	var input auth.MFALoginInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.MFAToken == "" || input.Code == "" {
+		logger.Log.Warn("Invalid MFA login request")
+		utils.WriteJSON(w, http.StatusBadRequest, auth.Response{Message: "Invalid request payload"})
+		return
+	}
+
+	result, err := h.authService.CompleteMFALogin(r.Context(), input.MFAToken, input.Code, utils.ClientInfo(r))
+	if err != nil {
+		var retry *errors2.RetryAfterError
+		switch {
+		case errors.As(err, &retry) && errors.Is(err, errors2.ErrAccountLocked):
+			utils.SetRetryAfter(w, retry.RetryAfter)
+			utils.WriteJSON(w, http.StatusLocked, auth.Response{Message: "Account is temporarily locked"})
+		case errors.As(err, &retry):
+			utils.SetRetryAfter(w, retry.RetryAfter)
+			utils.WriteJSON(w, http.StatusTooManyRequests, auth.Response{Message: "Too many login attempts"})
+		case errors.Is(err, errors2.ErrInvalidToken):
+			utils.WriteJSON(w, http.StatusUnauthorized, auth.Response{Message: "Invalid or expired MFA token"})
+		case errors.Is(err, errors2.ErrInvalidMFACode):
+			utils.WriteJSON(w, http.StatusUnauthorized, auth.Response{Message: "Invalid MFA code"})
+		case errors.Is(err, errors2.ErrAccountSuspended):
+			utils.WriteJSON(w, http.StatusForbidden, auth.Response{Message: "Account is suspended"})
+		case errors.Is(err, errors2.ErrValidation):
+			utils.WriteJSON(w, http.StatusBadRequest, auth.Response{Message: err.Error()})
+		default:
+			logger.Log.Error("MFA login failed", "error", err)
+			utils.WriteError(w, http.StatusInternalServerError, "failed to complete login")
+		}
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, loginResponse(result))
+}
+
+// LoginMFASetup godoc
+// @Summary      Set up mandatory MFA during login
+// @Description  Generate a TOTP secret for a user whose login returned mfa_enrollment_required. Confirm it with a code at /api/login/mfa.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input  body   auth.MFATokenInput  true  "MFA challenge token"
+// @Success      200  {object}  auth.MFASetupResponse
+// @Failure      400  {object}  auth.Response
+// @Failure      401  {object}  auth.Response
+// @Failure      409  {object}  auth.Response
+// @Failure      500  {object}  errors.ErrorResponse
+// @Router       /api/login/mfa/setup [post]
+func (h *AuthHandler) LoginMFASetup(w http.ResponseWriter, r *http.Request) {
+	var input auth.MFATokenInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.MFAToken == "" {
+		logger.Log.Warn("Invalid MFA setup request")
+		utils.WriteJSON(w, http.StatusBadRequest, auth.Response{Message: "Invalid request payload"})
+		return
+	}
+
+	setup, err := h.authService.MFAEnrollmentSetup(r.Context(), input.MFAToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, errors2.ErrInvalidToken):
+			utils.WriteJSON(w, http.StatusUnauthorized, auth.Response{Message: "Invalid or expired MFA token"})
+		case errors.Is(err, errors2.ErrMFAAlreadyEnabled):
+			utils.WriteJSON(w, http.StatusConflict, auth.Response{Message: "MFA is already enabled"})
+		default:
+			logger.Log.Error("MFA enrollment setup failed", "error", err)
+			utils.WriteError(w, http.StatusInternalServerError, "failed to set up MFA")
+		}
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.MFASetupResponse{
+		Secret:          setup.Secret,
+		ProvisioningURI: setup.ProvisioningURI,
+	})
+}
+
+// Refresh godoc
+// @Summary      Refresh tokens
+// @Description  Rotate the refresh token and return a new JWT pair. Reusing an already rotated refresh token revokes the login.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input  body   auth.RefreshTokenInput  true  "Refresh token"
+// @Success      200  {object}  auth.TokensResponse
+// @Failure      400  {object}  auth.Response
+// @Failure      401  {object}  auth.Response
+// @Failure      403  {object}  auth.Response
+// @Failure      500  {object}  errors.ErrorResponse
+// @Router       /api/refresh [post]
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var input auth.RefreshTokenInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.RefreshToken == "" {
+		logger.Log.Warn("Invalid refresh request")
+		utils.WriteJSON(w, http.StatusBadRequest, auth.Response{Message: "Invalid request payload"})
+		return
+	}
+
+	accessToken, refreshToken, err := h.authService.Refresh(r.Context(), input.RefreshToken, utils.ClientInfo(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, errors2.ErrUnauthorized):
+			utils.WriteJSON(w, http.StatusUnauthorized, auth.Response{Message: "Invalid or expired refresh token"})
+		case errors.Is(err, errors2.ErrAccountSuspended):
+			utils.WriteJSON(w, http.StatusForbidden, auth.Response{Message: "Account is suspended"})
+		default:
+			logger.Log.Error("Refresh failed", "error", err)
+			utils.WriteError(w, http.StatusInternalServerError, "failed to refresh tokens")
+		}
 		return
 	}
 
@@ -79,7 +230,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 // Logout godoc
 // @Summary      Logout user
-// @Description  Invalidate refresh token for the current user
+// @Description  End the current session, invalidate its refresh token and revoke the access token
 // @Tags         auth
 // @Produce      json
 // @Success      200  {object}  auth.Response
@@ -94,7 +245,7 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.authService.Logout(r.Context(), actor.UserID); err != nil {
+	if err := h.authService.Logout(r.Context(), actor); err != nil {
 		logger.Log.Error("Logout failed", "error", err)
 		utils.WriteError(w, http.StatusInternalServerError, "failed to logout")
 		return
@@ -102,3 +253,155 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 
 	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "Logout successfully"})
 }
+
+// ListSessions godoc
+// @Summary      List sessions
+// @Description  Returns active sessions (devices) of the current user
+// @Tags         sessions
+// @Produce      json
+// @Success      200  {array}   auth.SessionResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/sessions [get]
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(r.Context(), actor.UserID)
+	if err != nil {
+		logger.Log.Error("list sessions failed", "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, "failed to list sessions")
+		return
+	}
+
+	resp := make([]auth.SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		resp = append(resp, auth.SessionResponse{
+			ID:         s.ID,
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			CreatedAt:  s.CreatedAt,
+			LastUsedAt: s.LastUsedAt,
+			Current:    s.ID == actor.SessionID,
+		})
+	}
+
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+// RevokeSession godoc
+// @Summary      Revoke session
+// @Description  Logs out the given session of the current user
+// @Tags         sessions
+// @Produce      json
+// @Param        id   path   string  true  "Session ID"
+// @Success      200  {object}  auth.Response
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if err := h.authService.RevokeSession(r.Context(), actor.UserID, id); err != nil {
+		if errors.Is(err, errors2.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, "session not found")
+			return
+		}
+		logger.Log.Error("revoke session failed", "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, "failed to revoke session")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "Session revoked"})
+}
+
+// RevokeOtherSessions godoc
+// @Summary      Log out everywhere else
+// @Description  Revokes every session of the current user except the one making the request
+// @Tags         sessions
+// @Produce      json
+// @Success      200  {object}  auth.Response
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/sessions [delete]
+func (h *AuthHandler) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.authService.RevokeOtherSessions(r.Context(), actor.UserID, actor.SessionID); err != nil {
+		logger.Log.Error("revoke other sessions failed", "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, "failed to revoke sessions")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "Other sessions revoked"})
+}
+
+// UnlockUser godoc
+// @Summary      Unlock user login
+// @Description  Lifts a login lockout caused by repeated failed attempts (admin only)
+// @Tags         admin
+// @Produce      json
+// @Param        id   path   int  true  "User ID"
+// @Success      200  {object}  auth.Response
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/users/{id}/unlock [post]
+func (h *AuthHandler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.authService.UnlockUser(r.Context(), actor, id); err != nil {
+		switch {
+		case errors.Is(err, errors2.ErrForbidden):
+			utils.WriteError(w, http.StatusForbidden, "forbidden")
+		case errors.Is(err, errors2.ErrNotFound):
+			utils.WriteError(w, http.StatusNotFound, "user not found")
+		default:
+			logger.Log.Error("unlock user failed", "error", err)
+			utils.WriteError(w, http.StatusInternalServerError, "failed to unlock user")
+		}
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "User unlocked"})
+}
+
+func loginResponse(result *models.LoginResult) auth.LoginResponse {
+	return auth.LoginResponse{
+		AccessToken:           result.AccessToken,
+		RefreshToken:          result.RefreshToken,
+		MFARequired:           result.MFAToken != "",
+		MFAEnrollmentRequired: result.MFAEnrollment,
+		MFAToken:              result.MFAToken,
+		RecoveryCodes:         result.RecoveryCodes,
+	}
+}
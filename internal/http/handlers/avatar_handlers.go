@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"news-api/internal/dto/auth"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/dto/user"
+	"news-api/internal/models"
+	"news-api/internal/service/interfaces"
+	"news-api/pkg/logger"
+	"news-api/utils"
+)
+
+// multipartOverhead is allowed on top of the file for the rest of the form.
+const multipartOverhead = 64 << 10
+
+type AvatarHandler struct {
+	avatarService interfaces.AvatarService
+	maxBytes      int64
+}
+
+func NewAvatarHandler(avatarService interfaces.AvatarService, maxBytes int64) *AvatarHandler {
+	return &AvatarHandler{avatarService: avatarService, maxBytes: maxBytes}
+}
+
+// Upload godoc
+// @Summary      Upload avatar
+// @Description  Replaces the avatar of the current user. Accepts JPEG, PNG, GIF or WebP in the "avatar" form field; the image is cropped to a square, stripped of metadata and stored in several sizes.
+// @Tags         profile
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        avatar  formData  file  true  "Image"
+// @Success      200  {object}  user.AvatarResponse
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      413  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/me/avatar [post]
+func (h *AvatarHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBytes+multipartOverhead)
+	file, _, err := r.FormFile("avatar")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, "file is too large")
+			return
+		}
+		utils.WriteError(w, http.StatusBadRequest, "avatar file is required")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, h.maxBytes+1))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "failed to read file")
+		return
+	}
+
+	urls, err := h.avatarService.Upload(r.Context(), actor.UserID, data)
+	if err != nil {
+		writeAvatarError(w, err, "failed to upload avatar")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, user.AvatarResponse{
+		URL:      urls[models.DefaultAvatarVariant],
+		Variants: urls,
+	})
+}
+
+// Remove godoc
+// @Summary      Remove avatar
+// @Description  Deletes the avatar of the current user
+// @Tags         profile
+// @Produce      json
+// @Success      200  {object}  auth.Response
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/me/avatar [delete]
+func (h *AvatarHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.avatarService.Remove(r.Context(), actor.UserID); err != nil {
+		writeAvatarError(w, err, "failed to remove avatar")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "Avatar removed"})
+}
+
+func writeAvatarError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, errors2.ErrFileTooLarge):
+		utils.WriteError(w, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, errors2.ErrValidation):
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, errors2.ErrNotFound):
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+	default:
+		logger.Log.Error(message, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, message)
+	}
+}
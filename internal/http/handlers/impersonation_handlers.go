@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"news-api/internal/dto/user"
+	"news-api/internal/service/interfaces"
+	"news-api/utils"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type ImpersonationHandler struct {
+	impersonationService interfaces.ImpersonationService
+}
+
+func NewImpersonationHandler(impersonationService interfaces.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{impersonationService: impersonationService}
+}
+
+// Impersonate godoc
+// @Summary      Impersonate user
+// @Description  Issues a short-lived access token acting as the user, for support. The token has no refresh token, carries the admin in its "act" claim, and every response to it has the X-Impersonated-By header. It cannot be used for admin operations or to change credentials of the account. Admins and other privileged users cannot be impersonated. Every impersonation is recorded with the reason (permission: users:impersonate)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id     path   int                      true  "User ID"
+// @Param        input  body   user.ImpersonateRequest  true  "Reason"
+// @Success      200  {object}  user.ImpersonationResponse
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/users/{id}/impersonate [post]
+func (h *ImpersonationHandler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var input user.ImpersonateRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	accessToken, imp, err := h.impersonationService.Impersonate(r.Context(), actor, id, input.Reason, utils.ClientInfo(r))
+	if err != nil {
+		writeUserAdminError(w, err, "failed to impersonate user")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	utils.WriteJSON(w, http.StatusOK, user.ImpersonationResponse{
+		AccessToken:    accessToken,
+		ExpiresAt:      imp.ExpiresAt,
+		UserID:         imp.UserID,
+		ImpersonatorID: imp.AdminID,
+	})
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+	"news-api/pkg/token"
+	"news-api/utils"
+)
+
+type JWKSHandler struct {
+	jwtManager *token.JWTManager
+}
+
+func NewJWKSHandler(jwtManager *token.JWTManager) *JWKSHandler {
+	return &JWKSHandler{jwtManager: jwtManager}
+}
+
+// JWKS godoc
+// @Summary      JSON Web Key Set
+// @Description  Public keys for verifying tokens issued by this service offline. Empty when tokens are signed with HS256.
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  token.JWKS
+// @Router       /.well-known/jwks.json [get]
+func (h *JWKSHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	utils.WriteJSON(w, http.StatusOK, h.jwtManager.JWKS())
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"news-api/internal/dto/auth"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/service/interfaces"
+	"news-api/pkg/logger"
+	"news-api/utils"
+)
+
+type MagicLinkHandler struct {
+	magicLinkService interfaces.MagicLinkService
+}
+
+func NewMagicLinkHandler(magicLinkService interfaces.MagicLinkService) *MagicLinkHandler {
+	return &MagicLinkHandler{magicLinkService: magicLinkService}
+}
+
+// SendLink godoc
+// @Summary      Request login link
+// @Description  Emails a single-use login link to the account with the email, if there is one. The response is the same for unknown emails.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input  body   auth.MagicLinkInput  true  "Account email"
+// @Success      202  {object}  auth.Response
+// @Failure      400  {object}  auth.Response
+// @Failure      500  {object}  errors.ErrorResponse
+// @Router       /api/login/magic [post]
+func (h *MagicLinkHandler) SendLink(w http.ResponseWriter, r *http.Request) {
+	var input auth.MagicLinkInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Email == "" {
+		utils.WriteJSON(w, http.StatusBadRequest, auth.Response{Message: "Invalid request payload"})
+		return
+	}
+
+	if err := h.magicLinkService.SendLink(r.Context(), input.Email); err != nil {
+		logger.Log.Error("magic link failed", "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, "failed to process request")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusAccepted, auth.Response{Message: "If the account exists, a login link has been sent"})
+}
+
+// Login godoc
+// @Summary      Log in with a login link
+// @Description  Exchanges the token from a login link for JWT tokens, or for an MFA challenge as /api/login does. The link works once.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input  body   auth.MagicLinkLoginInput  true  "Token from the link"
+// @Success      200  {object}  auth.LoginResponse
+// @Failure      400  {object}  auth.Response
+// @Failure      401  {object}  auth.Response
+// @Failure      403  {object}  auth.Response
+// @Failure      500  {object}  errors.ErrorResponse
+// @Router       /api/login/magic/verify [post]
+func (h *MagicLinkHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var input auth.MagicLinkLoginInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Token == "" {
+		utils.WriteJSON(w, http.StatusBadRequest, auth.Response{Message: "Invalid request payload"})
+		return
+	}
+
+	result, err := h.magicLinkService.Login(r.Context(), input.Token, utils.ClientInfo(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, errors2.ErrInvalidToken):
+			utils.WriteJSON(w, http.StatusUnauthorized, auth.Response{Message: "Invalid, used or expired login link"})
+		case errors.Is(err, errors2.ErrAccountSuspended):
+			utils.WriteJSON(w, http.StatusForbidden, auth.Response{Message: "Account is suspended"})
+		default:
+			logger.Log.Error("magic link login failed", "error", err)
+			utils.WriteError(w, http.StatusInternalServerError, "failed to complete login")
+		}
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, loginResponse(result))
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"news-api/internal/dto/auth"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/service/interfaces"
+	"news-api/pkg/logger"
+	"news-api/utils"
+)
+
+type MFAHandler struct {
+	mfaService interfaces.MFAService
+}
+
+func NewMFAHandler(mfaService interfaces.MFAService) *MFAHandler {
+	return &MFAHandler{mfaService: mfaService}
+}
+
+// Setup godoc
+// @Summary      Start MFA setup
+// @Description  Generates a TOTP secret and an otpauth:// provisioning URI to show as a QR code. MFA is enabled only after confirming a code at /api/mfa/enable.
+// @Tags         mfa
+// @Produce      json
+// @Success      200  {object}  auth.MFASetupResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      409  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/mfa/setup [post]
+func (h *MFAHandler) Setup(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	setup, err := h.mfaService.Setup(r.Context(), actor.UserID)
+	if err != nil {
+		writeMFAError(w, err, "failed to set up MFA")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.MFASetupResponse{
+		Secret:          setup.Secret,
+		ProvisioningURI: setup.ProvisioningURI,
+	})
+}
+
+// Enable godoc
+// @Summary      Enable MFA
+// @Description  Confirms the secret from /api/mfa/setup with a current TOTP code and returns one-time recovery codes. They are shown only once.
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Param        input  body   auth.MFACodeInput  true  "TOTP code"
+// @Success      200  {object}  auth.RecoveryCodesResponse
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      409  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/mfa/enable [post]
+func (h *MFAHandler) Enable(w http.ResponseWriter, r *http.Request) {
+	actor, code, ok := h.codeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	codes, err := h.mfaService.Enable(r.Context(), actor, code)
+	if err != nil {
+		writeMFAError(w, err, "failed to enable MFA")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Disable godoc
+// @Summary      Disable MFA
+// @Description  Turns MFA off after checking a TOTP or recovery code. Not allowed for admins when MFA is mandatory for them.
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Param        input  body   auth.MFACodeInput  true  "TOTP or recovery code"
+// @Success      200  {object}  auth.Response
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      409  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/mfa/disable [post]
+func (h *MFAHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	actor, code, ok := h.codeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.mfaService.Disable(r.Context(), actor, code); err != nil {
+		writeMFAError(w, err, "failed to disable MFA")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "MFA disabled"})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      Regenerate recovery codes
+// @Description  Replaces all recovery codes after checking a TOTP or recovery code
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Param        input  body   auth.MFACodeInput  true  "TOTP or recovery code"
+// @Success      200  {object}  auth.RecoveryCodesResponse
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      409  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	actor, code, ok := h.codeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(r.Context(), actor, code)
+	if err != nil {
+		writeMFAError(w, err, "failed to regenerate recovery codes")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// codeRequest reads the current user id and the code from the request body.
+func (h *MFAHandler) codeRequest(w http.ResponseWriter, r *http.Request) (int, string, bool) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return 0, "", false
+	}
+
+	var input auth.MFACodeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Code == "" {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request payload")
+		return 0, "", false
+	}
+	return actor.UserID, input.Code, true
+}
+
+func writeMFAError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, errors2.ErrInvalidMFACode):
+		utils.WriteError(w, http.StatusBadRequest, "invalid MFA code")
+	case errors.Is(err, errors2.ErrValidation):
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, errors2.ErrMFAAlreadyEnabled):
+		utils.WriteError(w, http.StatusConflict, "MFA already enabled")
+	case errors.Is(err, errors2.ErrMFANotEnabled):
+		utils.WriteError(w, http.StatusConflict, "MFA not enabled")
+	case errors.Is(err, errors2.ErrForbidden):
+		utils.WriteError(w, http.StatusForbidden, "MFA is mandatory for your role")
+	case errors.Is(err, errors2.ErrNotFound):
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+	default:
+		logger.Log.Error(message, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, message)
+	}
+}
@@ -26,15 +26,23 @@ func NewNewsHandler(newsService interfaces.NewsService) *NewsHandler {
 
 // ListNews godoc
 // @Summary      Get news list
-// @Description  Returns list of news with pagination, filtering and search
+// @Description  Returns list of news with pagination, filtering by author, category and tag, and search. Only published news is listed unless status asks for another one; unpublished news needs a token and lists only the caller's own unless they hold news:publish or news:update:any
 // @Tags         news
 // @Produce      json
 // @Param        limit     query   int     false  "Limit (default 10)"
 // @Param        offset    query   int     false  "Offset (default 0)"
 // @Param        author_id query   int     false  "Filter by author id"
 // @Param        search    query   string  false  "Search by title"
+// @Param        status    query   string  false  "Editorial status (default published)"  Enums(draft, in_review, scheduled, published, archived)
+// @Param        category  query   string  false  "Category slug; news of its subcategories is included"
+// @Param        tag       query   string  false  "Tag"
 // @Success      200  {array}   models.News
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
 // @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Router       /api/news [get]
 func (h *NewsHandler) ListNews(w http.ResponseWriter, r *http.Request) {
 	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
@@ -53,11 +61,31 @@ func (h *NewsHandler) ListNews(w http.ResponseWriter, r *http.Request) {
 	if search := r.URL.Query().Get("search"); search != "" {
 		params.Search = &search
 	}
+	if status := r.URL.Query().Get("status"); status != "" {
+		params.Status = &status
+	}
+	if category := r.URL.Query().Get("category"); category != "" {
+		params.Category = &category
+	}
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		params.Tag = &tag
+	}
 
-	news, err := h.newsService.ListNews(r.Context(), params)
+	// The route is public; a token only widens what can be listed.
+	actor, _ := getActor(r)
+	news, err := h.newsService.ListNews(r.Context(), actor, params)
 	if err != nil {
-		logger.Log.Error("list news failed", "error", err)
-		utils.WriteError(w, http.StatusInternalServerError, "failed to list news")
+		switch {
+		case errors.Is(err, errors2.ErrUnauthorized):
+			utils.WriteError(w, http.StatusUnauthorized, "log in to list unpublished news")
+		case errors.Is(err, errors2.ErrForbidden):
+			utils.WriteError(w, http.StatusForbidden, "forbidden")
+		case errors.Is(err, errors2.ErrValidation):
+			utils.WriteError(w, http.StatusBadRequest, err.Error())
+		default:
+			logger.Log.Error("list news failed", "error", err)
+			utils.WriteError(w, http.StatusInternalServerError, "failed to list news")
+		}
 		return
 	}
 
@@ -66,6 +94,7 @@ func (h *NewsHandler) ListNews(w http.ResponseWriter, r *http.Request) {
 
 // GetNewsByID godoc
 // @Summary      Get news by ID
+// @Description  Published news is public. Unpublished news is found only with a token of its author or of someone who may edit or publish it
 // @Tags         news
 // @Produce      json
 // @Param        id   path   int  true  "News ID"
@@ -73,6 +102,8 @@ func (h *NewsHandler) ListNews(w http.ResponseWriter, r *http.Request) {
 // @Failure      400  {object}  errors.ErrorResponse
 // @Failure      404  {object}  errors.ErrorResponse
 // @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Router       /api/news/{id} [get]
 func (h *NewsHandler) GetNewsByID(w http.ResponseWriter, r *http.Request) {
 	idStr := mux.Vars(r)["id"]
@@ -82,7 +113,8 @@ func (h *NewsHandler) GetNewsByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	n, err := h.newsService.GetByIDNews(r.Context(), id)
+	actor, _ := getActor(r)
+	n, err := h.newsService.GetByIDNews(r.Context(), actor, id)
 	if err != nil {
 		if errors.Is(err, errors2.ErrNotFound) {
 			utils.WriteError(w, http.StatusNotFound, "news not found")
@@ -98,16 +130,18 @@ func (h *NewsHandler) GetNewsByID(w http.ResponseWriter, r *http.Request) {
 
 // CreateNews godoc
 // @Summary      Create news
+// @Description  News starts as a draft visible to its author; submit it for review with POST /api/news/{id}/status. category_ids must be existing categories; tags are free-form and created on first use
 // @Tags         news
 // @Accept       json
 // @Produce      json
 // @Param        input  body   news.News  true  "News input"
-// @Success      201  {object}  news.News
+// @Success      201  {object}  models.News
 // @Failure      400  {object}  errors.ErrorResponse
 // @Failure      401  {object}  errors.ErrorResponse
 // @Failure      403  {object}  errors.ErrorResponse
 // @Failure      500  {object}  errors.ErrorResponse
 // @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Router       /api/news [post]
 func (h *NewsHandler) CreateNews(w http.ResponseWriter, r *http.Request) {
 	var n news.News
@@ -125,10 +159,14 @@ func (h *NewsHandler) CreateNews(w http.ResponseWriter, r *http.Request) {
 	newsTemp := models.News{
 		Title:       n.Title,
 		Description: n.Description,
+		CategoryIDs: n.CategoryIDs,
+		Tags:        n.Tags,
 	}
 
 	if err := h.newsService.CreateNews(r.Context(), actor, &newsTemp); err != nil {
 		switch {
+		case errors.Is(err, errors2.ErrEmailNotVerified):
+			utils.WriteError(w, http.StatusForbidden, "email not verified")
 		case errors.Is(err, errors2.ErrForbidden):
 			utils.WriteError(w, http.StatusForbidden, "forbidden")
 		case errors.Is(err, errors2.ErrValidation):
@@ -140,11 +178,12 @@ func (h *NewsHandler) CreateNews(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusCreated, n)
+	utils.WriteJSON(w, http.StatusCreated, newsTemp)
 }
 
 // UpdateNews godoc
 // @Summary      Update news
+// @Description  Saves the text as a new revision. category_ids and tags replace those of the news when given and are kept when left out
 // @Tags         news
 // @Accept       json
 // @Produce      json
@@ -157,6 +196,7 @@ func (h *NewsHandler) CreateNews(w http.ResponseWriter, r *http.Request) {
 // @Failure      404  {object}  errors.ErrorResponse
 // @Failure      500  {object}  errors.ErrorResponse
 // @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Router       /api/news/{id} [put]
 func (h *NewsHandler) UpdateNews(w http.ResponseWriter, r *http.Request) {
 	idStr := mux.Vars(r)["id"]
@@ -181,12 +221,14 @@ func (h *NewsHandler) UpdateNews(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.newsService.UpdateNews(r.Context(), actor, &n); err != nil {
 		switch {
+		case errors.Is(err, errors2.ErrEmailNotVerified):
+			utils.WriteError(w, http.StatusForbidden, "email not verified")
 		case errors.Is(err, errors2.ErrForbidden):
 			utils.WriteError(w, http.StatusForbidden, "forbidden")
 		case errors.Is(err, errors2.ErrNotFound):
 			utils.WriteError(w, http.StatusNotFound, "news not found")
 		case errors.Is(err, errors2.ErrValidation):
-			utils.WriteError(w, http.StatusBadRequest, "validation failed")
+			utils.WriteError(w, http.StatusBadRequest, err.Error())
 		default:
 			logger.Log.Error("update news failed", "error", err)
 			utils.WriteError(w, http.StatusInternalServerError, "failed to update news")
@@ -197,8 +239,307 @@ func (h *NewsHandler) UpdateNews(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJSON(w, http.StatusOK, n)
 }
 
+// ChangeNewsStatus godoc
+// @Summary      Change news status
+// @Description  Moves news through the editorial workflow. Whoever may edit the news submits a draft (draft → in_review) and may take it back (in_review → draft, scheduled → in_review); holders of news:publish publish or reject it (in_review → published, in_review → draft), schedule it (in_review or scheduled → scheduled, with a future publish_at), publish scheduled news early (scheduled → published) and archive published news (published → archived). unpublish_at archives scheduled or published news later on its own. Publishing sets published_at
+// @Tags         news
+// @Accept       json
+// @Produce      json
+// @Param        id     path   int                        true  "News ID"
+// @Param        input  body   news.ChangeStatusRequest  true  "New status"
+// @Success      200  {object}  models.News
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Router       /api/news/{id}/status [post]
+func (h *NewsHandler) ChangeNewsStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var input news.ChangeStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	n, err := h.newsService.ChangeStatus(r.Context(), actor, id, models.NewsStatusChange{
+		Status:      input.Status,
+		PublishAt:   input.PublishAt,
+		UnpublishAt: input.UnpublishAt,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, errors2.ErrEmailNotVerified):
+			utils.WriteError(w, http.StatusForbidden, "email not verified")
+		case errors.Is(err, errors2.ErrForbidden):
+			utils.WriteError(w, http.StatusForbidden, "forbidden")
+		case errors.Is(err, errors2.ErrNotFound):
+			utils.WriteError(w, http.StatusNotFound, "news not found")
+		case errors.Is(err, errors2.ErrValidation):
+			utils.WriteError(w, http.StatusBadRequest, err.Error())
+		default:
+			logger.Log.Error("change news status failed", "error", err)
+			utils.WriteError(w, http.StatusInternalServerError, "failed to change news status")
+		}
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, n)
+}
+
+// ListNewsRevisions godoc
+// @Summary      List news revisions
+// @Description  Every saved version of the news text, newest first: revision 1 as created and one more for each update or restore, with who saved it and when. Available to those who may edit the news and to holders of news:publish or news:update:any
+// @Tags         news
+// @Produce      json
+// @Param        id   path   int  true  "News ID"
+// @Success      200  {array}   models.NewsRevision
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Router       /api/news/{id}/revisions [get]
+func (h *NewsHandler) ListNewsRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	revisions, err := h.newsService.ListRevisions(r.Context(), actor, id)
+	if err != nil {
+		writeNewsError(w, err, "failed to list news revisions")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, revisions)
+}
+
+// DiffNewsRevisions godoc
+// @Summary      Compare news revisions
+// @Description  Line diff of the title and description between two revisions. to defaults to the latest revision and from to the one before to
+// @Tags         news
+// @Produce      json
+// @Param        id    path   int  true   "News ID"
+// @Param        from  query  int  false  "Older revision"
+// @Param        to    query  int  false  "Newer revision"
+// @Success      200  {object}  models.NewsRevisionDiff
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Router       /api/news/{id}/revisions/diff [get]
+func (h *NewsHandler) DiffNewsRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var revs [2]int
+	for i, name := range []string{"from", "to"} {
+		if s := r.URL.Query().Get(name); s != "" {
+			rev, err := strconv.Atoi(s)
+			if err != nil || rev <= 0 {
+				utils.WriteError(w, http.StatusBadRequest, "invalid "+name)
+				return
+			}
+			revs[i] = rev
+		}
+	}
+
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	diff, err := h.newsService.DiffRevisions(r.Context(), actor, id, revs[0], revs[1])
+	if err != nil {
+		writeNewsError(w, err, "failed to compare news revisions")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, diff)
+}
+
+// RestoreNewsRevision godoc
+// @Summary      Restore news revision
+// @Description  Puts the text of an earlier revision back. The restored text is saved as a new revision; the same rules as for updating the news apply
+// @Tags         news
+// @Produce      json
+// @Param        id   path   int  true  "News ID"
+// @Param        rev  path   int  true  "Revision to restore"
+// @Success      200  {object}  models.News
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Router       /api/news/{id}/revisions/{rev}/restore [post]
+func (h *NewsHandler) RestoreNewsRevision(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+	rev, err := strconv.Atoi(mux.Vars(r)["rev"])
+	if err != nil || rev <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid revision")
+		return
+	}
+
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	n, err := h.newsService.RestoreRevision(r.Context(), actor, id, rev)
+	if err != nil {
+		writeNewsError(w, err, "failed to restore news revision")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, n)
+}
+
+// ListTrash godoc
+// @Summary      List deleted news
+// @Description  Deleted news waiting in the trash, most recently deleted first. It is purged for good after NEWS_TRASH_RETENTION_DAYS (permission: news:trash)
+// @Tags         admin
+// @Produce      json
+// @Param        limit      query  int     false  "Limit (default 10, max 100)"
+// @Param        offset     query  int     false  "Offset (default 0)"
+// @Param        author_id  query  int     false  "Filter by author id"
+// @Param        search     query  string  false  "Search by title"
+// @Success      200  {object}  news.TrashListResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/news/trash [get]
+func (h *NewsHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	params := models.NewsListParams{Limit: limit, Offset: offset}
+	if authorStr := q.Get("author_id"); authorStr != "" {
+		if authorID, err := strconv.Atoi(authorStr); err == nil {
+			params.AuthorID = &authorID
+		}
+	}
+	if search := q.Get("search"); search != "" {
+		params.Search = &search
+	}
+
+	list, total, err := h.newsService.ListTrash(r.Context(), actor, params)
+	if err != nil {
+		writeNewsError(w, err, "failed to list deleted news")
+		return
+	}
+
+	params.Normalize()
+	utils.WriteJSON(w, http.StatusOK, news.TrashListResponse{
+		Items:  list,
+		Total:  total,
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	})
+}
+
+// RestoreNews godoc
+// @Summary      Restore deleted news
+// @Description  Takes news out of the trash with the status and schedule it had when deleted (permission: news:trash)
+// @Tags         admin
+// @Produce      json
+// @Param        id   path   int  true  "News ID"
+// @Success      200  {object}  models.News
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/news/{id}/restore [post]
+func (h *NewsHandler) RestoreNews(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	n, err := h.newsService.RestoreNews(r.Context(), actor, id)
+	if err != nil {
+		if errors.Is(err, errors2.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, "news not found in trash")
+			return
+		}
+		writeNewsError(w, err, "failed to restore news")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, n)
+}
+
+func writeNewsError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, errors2.ErrEmailNotVerified):
+		utils.WriteError(w, http.StatusForbidden, "email not verified")
+	case errors.Is(err, errors2.ErrForbidden):
+		utils.WriteError(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, errors2.ErrNotFound):
+		utils.WriteError(w, http.StatusNotFound, "news or revision not found")
+	case errors.Is(err, errors2.ErrValidation):
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		logger.Log.Error(message, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, message)
+	}
+}
+
 // DeleteNews godoc
 // @Summary      Delete news
+// @Description  Moves the news to the trash, from where admins can restore it until it is purged
 // @Tags         news
 // @Produce      json
 // @Param        id   path   int  true  "News ID"
@@ -209,6 +550,7 @@ func (h *NewsHandler) UpdateNews(w http.ResponseWriter, r *http.Request) {
 // @Failure      404  {object}  errors.ErrorResponse
 // @Failure      500  {object}  errors.ErrorResponse
 // @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Router       /api/news/{id} [delete]
 func (h *NewsHandler) DeleteNews(w http.ResponseWriter, r *http.Request) {
 	idStr := mux.Vars(r)["id"]
@@ -226,6 +568,8 @@ func (h *NewsHandler) DeleteNews(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.newsService.DeleteNews(r.Context(), actor, id); err != nil {
 		switch {
+		case errors.Is(err, errors2.ErrEmailNotVerified):
+			utils.WriteError(w, http.StatusForbidden, "email not verified")
 		case errors.Is(err, errors2.ErrForbidden):
 			utils.WriteError(w, http.StatusForbidden, "forbidden")
 		case errors.Is(err, errors2.ErrNotFound):
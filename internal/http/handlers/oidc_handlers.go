@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"news-api/internal/dto/auth"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/service/interfaces"
+	"news-api/pkg/logger"
+	"news-api/utils"
+)
+
+type OIDCHandler struct {
+	oidcService interfaces.OIDCService
+}
+
+func NewOIDCHandler(oidcService interfaces.OIDCService) *OIDCHandler {
+	return &OIDCHandler{oidcService: oidcService}
+}
+
+// Login godoc
+// @Summary      Start SSO login
+// @Description  Redirects to the corporate identity provider (OIDC authorization code flow with PKCE)
+// @Tags         auth
+// @Success      302
+// @Failure      502  {object}  errors.ErrorResponse
+// @Router       /api/auth/oidc/login [get]
+func (h *OIDCHandler) Login(w http.ResponseWriter, r *http.Request) {
+	authURL, err := h.oidcService.AuthURL(r.Context())
+	if err != nil {
+		logger.Log.Error("OIDC login failed", "error", err)
+		utils.WriteError(w, http.StatusBadGateway, "identity provider unavailable")
+		return
+	}
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback godoc
+// @Summary      Finish SSO login
+// @Description  Redirect target of the identity provider. Links the identity to an existing account with the same verified email or creates one, maps provider groups to roles and returns JWT tokens (or an MFA challenge, as /api/login does).
+// @Tags         auth
+// @Produce      json
+// @Param        code   query  string  true  "Authorization code"
+// @Param        state  query  string  true  "State from /api/auth/oidc/login"
+// @Success      200  {object}  auth.LoginResponse
+// @Failure      400  {object}  auth.Response
+// @Failure      401  {object}  auth.Response
+// @Failure      403  {object}  auth.Response
+// @Failure      409  {object}  auth.Response
+// @Failure      500  {object}  errors.ErrorResponse
+// @Router       /api/auth/oidc/callback [get]
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if idpErr := q.Get("error"); idpErr != "" {
+		logger.Log.Warn("OIDC provider returned error", slog.String("error", idpErr), slog.String("description", q.Get("error_description")))
+		utils.WriteJSON(w, http.StatusBadRequest, auth.Response{Message: "Login was cancelled or denied by the identity provider"})
+		return
+	}
+	if q.Get("code") == "" || q.Get("state") == "" {
+		utils.WriteJSON(w, http.StatusBadRequest, auth.Response{Message: "Invalid request payload"})
+		return
+	}
+
+	result, err := h.oidcService.Callback(r.Context(), q.Get("code"), q.Get("state"), utils.ClientInfo(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, errors2.ErrInvalidToken):
+			utils.WriteJSON(w, http.StatusBadRequest, auth.Response{Message: "Invalid or expired login state"})
+		case errors.Is(err, errors2.ErrUnauthorized):
+			utils.WriteJSON(w, http.StatusUnauthorized, auth.Response{Message: "Identity provider login failed"})
+		case errors.Is(err, errors2.ErrForbidden):
+			utils.WriteJSON(w, http.StatusForbidden, auth.Response{Message: "Your account is not allowed to use this service"})
+		case errors.Is(err, errors2.ErrAccountSuspended):
+			utils.WriteJSON(w, http.StatusForbidden, auth.Response{Message: "Account is suspended"})
+		case errors.Is(err, errors2.ErrAccountExists):
+			utils.WriteJSON(w, http.StatusConflict, auth.Response{Message: err.Error()})
+		case errors.Is(err, errors2.ErrValidation):
+			utils.WriteJSON(w, http.StatusBadRequest, auth.Response{Message: err.Error()})
+		default:
+			logger.Log.Error("OIDC callback failed", "error", err)
+			utils.WriteError(w, http.StatusInternalServerError, "failed to complete login")
+		}
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, loginResponse(result))
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"news-api/internal/dto/auth"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/service/interfaces"
+	"news-api/pkg/logger"
+	"news-api/utils"
+)
+
+type PasswordHandler struct {
+	passwordService interfaces.PasswordService
+}
+
+func NewPasswordHandler(passwordService interfaces.PasswordService) *PasswordHandler {
+	return &PasswordHandler{passwordService: passwordService}
+}
+
+// ForgotPassword godoc
+// @Summary      Request password reset
+// @Description  Sends a single-use password reset link to the email if an account exists. The response is the same for unknown emails.
+// @Tags         password
+// @Accept       json
+// @Produce      json
+// @Param        input  body   auth.ForgotPasswordInput  true  "Account email"
+// @Success      202  {object}  auth.Response
+// @Failure      400  {object}  auth.Response
+// @Failure      500  {object}  errors.ErrorResponse
+// @Router       /api/password/forgot [post]
+func (h *PasswordHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var input auth.ForgotPasswordInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Email == "" {
+		utils.WriteJSON(w, http.StatusBadRequest, auth.Response{Message: "Invalid request payload"})
+		return
+	}
+
+	if err := h.passwordService.ForgotPassword(r.Context(), input.Email); err != nil {
+		logger.Log.Error("forgot password failed", "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, "failed to process request")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusAccepted, auth.Response{Message: "If the account exists, a reset link has been sent"})
+}
+
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Sets a new password using a token from the reset email and ends all sessions of the user
+// @Tags         password
+// @Accept       json
+// @Produce      json
+// @Param        input  body   auth.ResetPasswordInput  true  "Reset token and new password"
+// @Success      200  {object}  auth.Response
+// @Failure      400  {object}  auth.Response
+// @Failure      500  {object}  errors.ErrorResponse
+// @Router       /api/password/reset [post]
+func (h *PasswordHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var input auth.ResetPasswordInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Token == "" {
+		utils.WriteJSON(w, http.StatusBadRequest, auth.Response{Message: "Invalid request payload"})
+		return
+	}
+
+	if err := h.passwordService.ResetPassword(r.Context(), input.Token, input.Password); err != nil {
+		switch {
+		case errors.Is(err, errors2.ErrValidation):
+			utils.WriteJSON(w, http.StatusBadRequest, auth.Response{Message: err.Error()})
+		case errors.Is(err, errors2.ErrInvalidToken):
+			utils.WriteJSON(w, http.StatusBadRequest, auth.Response{Message: "Invalid or expired reset token"})
+		default:
+			logger.Log.Error("reset password failed", "error", err)
+			utils.WriteError(w, http.StatusInternalServerError, "failed to reset password")
+		}
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "Password has been reset"})
+}
@@ -0,0 +1,345 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"news-api/internal/dto/auth"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/dto/user"
+	"news-api/internal/models"
+	"news-api/internal/service/interfaces"
+	"news-api/pkg/logger"
+	"news-api/utils"
+)
+
+type ProfileHandler struct {
+	profileService interfaces.ProfileService
+}
+
+func NewProfileHandler(profileService interfaces.ProfileService) *ProfileHandler {
+	return &ProfileHandler{profileService: profileService}
+}
+
+// Me godoc
+// @Summary      Current user
+// @Description  Returns the profile of the current user
+// @Tags         profile
+// @Produce      json
+// @Success      200  {object}  user.UserResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/me [get]
+func (h *ProfileHandler) Me(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	u, err := h.profileService.GetProfile(r.Context(), actor.UserID)
+	if err != nil {
+		writeProfileError(w, err, "failed to load profile")
+		return
+	}
+
+	resp := userResponse(u)
+	resp.ImpersonatedBy = actor.ImpersonatorID
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+// UpdateMe godoc
+// @Summary      Update profile
+// @Description  Changes the first and/or last name of the current user
+// @Tags         profile
+// @Accept       json
+// @Produce      json
+// @Param        input  body   user.UpdateProfileRequest  true  "Fields to change"
+// @Success      200  {object}  user.UserResponse
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/me [patch]
+func (h *ProfileHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var input user.UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	u, err := h.profileService.UpdateProfile(r.Context(), actor.UserID, input)
+	if err != nil {
+		writeProfileError(w, err, "failed to update profile")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, userResponse(u))
+}
+
+// ChangePassword godoc
+// @Summary      Change password
+// @Description  Sets a new password after checking the current one. All other sessions of the user are ended.
+// @Tags         profile
+// @Accept       json
+// @Produce      json
+// @Param        input  body   user.ChangePasswordRequest  true  "Current and new password"
+// @Success      200  {object}  auth.Response
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      429  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/me/password [post]
+func (h *ProfileHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var input user.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.CurrentPassword == "" || input.NewPassword == "" {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	if err := h.profileService.ChangePassword(r.Context(), actor, input, utils.ClientInfo(r)); err != nil {
+		writeProfileError(w, err, "failed to change password")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "Password changed"})
+}
+
+// ChangeEmail godoc
+// @Summary      Change email
+// @Description  Sends a confirmation link to the new address. The email of the account changes once the link is confirmed at /api/verify-email.
+// @Tags         profile
+// @Accept       json
+// @Produce      json
+// @Param        input  body   user.ChangeEmailRequest  true  "New email and current password"
+// @Success      202  {object}  auth.Response
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      409  {object}  errors.ErrorResponse
+// @Failure      429  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/me/email [post]
+func (h *ProfileHandler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var input user.ChangeEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Email == "" || input.Password == "" {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	if err := h.profileService.RequestEmailChange(r.Context(), actor.UserID, input, utils.ClientInfo(r)); err != nil {
+		writeProfileError(w, err, "failed to change email")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusAccepted, auth.Response{Message: "Confirmation link sent to the new email"})
+}
+
+// Export godoc
+// @Summary      Export personal data
+// @Description  Returns the profile, news and sessions of the current user as a ZIP archive of JSON files (default) or as one JSON document (format=json)
+// @Tags         profile
+// @Produce      application/zip
+// @Produce      json
+// @Param        format  query  string  false  "Archive format"  Enums(zip, json)
+// @Success      200  {object}  user.ExportResponse
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/me/export [get]
+func (h *ProfileHandler) Export(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "zip"
+	}
+	if format != "zip" && format != "json" {
+		utils.WriteError(w, http.StatusBadRequest, "format must be zip or json")
+		return
+	}
+
+	export, err := h.profileService.Export(r.Context(), actor.UserID)
+	if err != nil {
+		writeProfileError(w, err, "failed to export data")
+		return
+	}
+	resp := exportResponse(export)
+
+	name := fmt.Sprintf("news-api-export-%d-%s", export.User.ID, export.ExportedAt.UTC().Format("20060102"))
+	w.Header().Set("Cache-Control", "no-store")
+	if format == "json" {
+		w.Header().Set("Content-Disposition", `attachment; filename="`+name+`.json"`)
+		utils.WriteJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for file, part := range map[string]any{
+		"profile.json":  resp.Profile,
+		"news.json":     resp.News,
+		"sessions.json": resp.Sessions,
+	} {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: file, Method: zip.Deflate, Modified: export.ExportedAt})
+		if err == nil {
+			enc := json.NewEncoder(f)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(part)
+		}
+		if err != nil {
+			logger.Log.Error("failed to build export archive", "error", err)
+			utils.WriteError(w, http.StatusInternalServerError, "failed to export data")
+			return
+		}
+	}
+	if err := zw.Close(); err != nil {
+		logger.Log.Error("failed to build export archive", "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, "failed to export data")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`.zip"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// RequestDeletion godoc
+// @Summary      Delete account
+// @Description  Schedules the account of the current user for deletion after a grace period. Until then the user can log in and cancel it. The user's news is then anonymised or transferred, as configured, not deleted.
+// @Tags         profile
+// @Accept       json
+// @Produce      json
+// @Param        input  body   user.DeleteAccountRequest  true  "Current password"
+// @Success      202  {object}  user.DeletionScheduledResponse
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      429  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/me/deletion [post]
+func (h *ProfileHandler) RequestDeletion(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var input user.DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Password == "" {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	at, err := h.profileService.RequestDeletion(r.Context(), actor.UserID, input.Password, utils.ClientInfo(r))
+	if err != nil {
+		writeProfileError(w, err, "failed to schedule deletion")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusAccepted, user.DeletionScheduledResponse{DeletionScheduledAt: at})
+}
+
+// CancelDeletion godoc
+// @Summary      Cancel account deletion
+// @Description  Cancels a pending deletion of the current user's account
+// @Tags         profile
+// @Produce      json
+// @Success      200  {object}  auth.Response
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/me/deletion [delete]
+func (h *ProfileHandler) CancelDeletion(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.profileService.CancelDeletion(r.Context(), actor.UserID); err != nil {
+		if errors.Is(err, errors2.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, "no deletion is scheduled")
+			return
+		}
+		writeProfileError(w, err, "failed to cancel deletion")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "Account deletion cancelled"})
+}
+
+func exportResponse(e *models.UserDataExport) user.ExportResponse {
+	u := e.User
+	return user.ExportResponse{
+		ExportedAt: e.ExportedAt,
+		Profile: user.ExportProfile{
+			ID:                  u.ID,
+			FirstName:           u.FirstName,
+			LastName:            u.LastName,
+			Email:               u.Email,
+			Role:                u.Role,
+			Avatar:              u.Avatar,
+			EmailVerifiedAt:     u.EmailVerifiedAt,
+			MFAEnabledAt:        u.MFAEnabledAt,
+			SuspendedAt:         u.SuspendedAt,
+			DeletionScheduledAt: u.DeletionScheduledAt,
+			CreatedAt:           u.CreatedAt,
+		},
+		News:     e.News,
+		Sessions: e.Sessions,
+	}
+}
+
+func writeProfileError(w http.ResponseWriter, err error, message string) {
+	var retry *errors2.RetryAfterError
+	switch {
+	case errors.As(err, &retry):
+		utils.SetRetryAfter(w, retry.RetryAfter)
+		utils.WriteError(w, http.StatusTooManyRequests, "too many attempts")
+	case errors.Is(err, errors2.ErrValidation):
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, errors2.ErrWrongPassword):
+		utils.WriteError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, errors2.ErrAccountExists):
+		utils.WriteError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, errors2.ErrNotFound):
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+	default:
+		logger.Log.Error(message, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"news-api/internal/dto/auth"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/dto/role"
+	"news-api/internal/models"
+	"news-api/internal/service/interfaces"
+	"news-api/pkg/logger"
+	"news-api/utils"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type RoleHandler struct {
+	roleService interfaces.RoleService
+}
+
+func NewRoleHandler(roleService interfaces.RoleService) *RoleHandler {
+	return &RoleHandler{roleService: roleService}
+}
+
+// ListRoles godoc
+// @Summary      List roles
+// @Description  Returns all roles with their permissions (permission: roles:manage)
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   role.RoleResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/roles [get]
+func (h *RoleHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	roles, err := h.roleService.ListRoles(r.Context(), actor)
+	if err != nil {
+		writeRoleError(w, err, "failed to list roles")
+		return
+	}
+
+	resp := make([]role.RoleResponse, 0, len(roles))
+	for _, rl := range roles {
+		resp = append(resp, roleResponse(rl))
+	}
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+// ListPermissions godoc
+// @Summary      List permissions
+// @Description  Returns every permission that can be granted to a role (permission: roles:manage)
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   role.PermissionResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/permissions [get]
+func (h *RoleHandler) ListPermissions(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	perms, err := h.roleService.ListPermissions(r.Context(), actor)
+	if err != nil {
+		writeRoleError(w, err, "failed to list permissions")
+		return
+	}
+
+	resp := make([]role.PermissionResponse, 0, len(perms))
+	for _, p := range perms {
+		resp = append(resp, role.PermissionResponse{Name: p.Name, Description: p.Description})
+	}
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+// CreateRole godoc
+// @Summary      Create role
+// @Description  Defines a new role with a set of permissions (permission: roles:manage)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        input  body   role.CreateRoleRequest  true  "Role"
+// @Success      201  {object}  role.RoleResponse
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      409  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/roles [post]
+func (h *RoleHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var input role.CreateRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	created, err := h.roleService.CreateRole(r.Context(), actor, input)
+	if err != nil {
+		writeRoleError(w, err, "failed to create role")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, roleResponse(*created))
+}
+
+// UpdateRole godoc
+// @Summary      Update role
+// @Description  Replaces the description and permissions of a role. The admin role cannot be changed (permission: roles:manage)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        name   path   string                  true  "Role name"
+// @Param        input  body   role.UpdateRoleRequest  true  "Role"
+// @Success      200  {object}  role.RoleResponse
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/roles/{name} [put]
+func (h *RoleHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var input role.UpdateRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	updated, err := h.roleService.UpdateRole(r.Context(), actor, mux.Vars(r)["name"], input)
+	if err != nil {
+		writeRoleError(w, err, "failed to update role")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, roleResponse(*updated))
+}
+
+// DeleteRole godoc
+// @Summary      Delete role
+// @Description  Deletes a role that is not a system role and not assigned to any user (permission: roles:manage)
+// @Tags         admin
+// @Produce      json
+// @Param        name  path  string  true  "Role name"
+// @Success      200  {object}  auth.Response
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      409  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/roles/{name} [delete]
+func (h *RoleHandler) DeleteRole(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.roleService.DeleteRole(r.Context(), actor, mux.Vars(r)["name"]); err != nil {
+		writeRoleError(w, err, "failed to delete role")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "Role deleted"})
+}
+
+// AssignRole godoc
+// @Summary      Assign role to user
+// @Description  Changes the role of a user and ends their sessions so the new role applies immediately. The caller must hold every privileged permission of both the current and the new role (permission: users:manage)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id     path   int                     true  "User ID"
+// @Param        input  body   role.AssignRoleRequest  true  "Role"
+// @Success      200  {object}  auth.Response
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/users/{id}/role [put]
+func (h *RoleHandler) AssignRole(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var input role.AssignRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Role == "" {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	if err := h.roleService.AssignRole(r.Context(), actor, id, input.Role); err != nil {
+		writeRoleError(w, err, "failed to assign role")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "Role assigned"})
+}
+
+func roleResponse(r models.Role) role.RoleResponse {
+	perms := r.Permissions
+	if perms == nil {
+		perms = []string{}
+	}
+	return role.RoleResponse{
+		Name:        r.Name,
+		Description: r.Description,
+		System:      r.System,
+		Permissions: perms,
+		CreatedAt:   r.CreatedAt,
+	}
+}
+
+func writeRoleError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, errors2.ErrForbidden):
+		utils.WriteError(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, errors2.ErrValidation):
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, errors2.ErrNotFound):
+		utils.WriteError(w, http.StatusNotFound, "not found")
+	case errors.Is(err, errors2.ErrRoleExists), errors.Is(err, errors2.ErrRoleInUse):
+		utils.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		logger.Log.Error(message, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, message)
+	}
+}
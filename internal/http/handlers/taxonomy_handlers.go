@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"news-api/internal/dto/auth"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/dto/taxonomy"
+	"news-api/internal/service/interfaces"
+	"news-api/pkg/logger"
+	"news-api/utils"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type TaxonomyHandler struct {
+	taxonomyService interfaces.TaxonomyService
+}
+
+func NewTaxonomyHandler(taxonomyService interfaces.TaxonomyService) *TaxonomyHandler {
+	return &TaxonomyHandler{taxonomyService: taxonomyService}
+}
+
+// ListCategories godoc
+// @Summary      List categories
+// @Description  Returns every category ordered by name. Categories form a tree through parent_id; filter news by a category with GET /api/news?category={slug}
+// @Tags         taxonomy
+// @Produce      json
+// @Success      200  {array}   models.Category
+// @Failure      500  {object}  errors.ErrorResponse
+// @Router       /api/categories [get]
+func (h *TaxonomyHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.taxonomyService.ListCategories(r.Context())
+	if err != nil {
+		writeTaxonomyError(w, err, "failed to list categories")
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, categories)
+}
+
+// CreateCategory godoc
+// @Summary      Create category
+// @Description  Adds a category, under parent_id if given (permission: taxonomy:manage)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        input  body   taxonomy.CategoryRequest  true  "Category"
+// @Success      201  {object}  models.Category
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      409  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/categories [post]
+func (h *TaxonomyHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var input taxonomy.CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	created, err := h.taxonomyService.CreateCategory(r.Context(), actor, input)
+	if err != nil {
+		writeTaxonomyError(w, err, "failed to create category")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, created)
+}
+
+// UpdateCategory godoc
+// @Summary      Update category
+// @Description  Replaces the name, slug, description and parent of a category. A category cannot be moved under itself or its subcategories (permission: taxonomy:manage)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id     path   int                       true  "Category ID"
+// @Param        input  body   taxonomy.CategoryRequest  true  "Category"
+// @Success      200  {object}  models.Category
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      409  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/categories/{id} [put]
+func (h *TaxonomyHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var input taxonomy.CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	updated, err := h.taxonomyService.UpdateCategory(r.Context(), actor, id, input)
+	if err != nil {
+		writeTaxonomyError(w, err, "failed to update category")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, updated)
+}
+
+// DeleteCategory godoc
+// @Summary      Delete category
+// @Description  Deletes a category that has no subcategories and takes it off its news (permission: taxonomy:manage)
+// @Tags         admin
+// @Produce      json
+// @Param        id  path  int  true  "Category ID"
+// @Success      200  {object}  auth.Response
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      409  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/categories/{id} [delete]
+func (h *TaxonomyHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.taxonomyService.DeleteCategory(r.Context(), actor, id); err != nil {
+		writeTaxonomyError(w, err, "failed to delete category")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "Category deleted"})
+}
+
+// ListTags godoc
+// @Summary      List tags
+// @Description  Returns every tag with the number of news carrying it; filter news by a tag with GET /api/news?tag={name}
+// @Tags         taxonomy
+// @Produce      json
+// @Success      200  {array}   models.Tag
+// @Failure      500  {object}  errors.ErrorResponse
+// @Router       /api/tags [get]
+func (h *TaxonomyHandler) ListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.taxonomyService.ListTags(r.Context())
+	if err != nil {
+		writeTaxonomyError(w, err, "failed to list tags")
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, tags)
+}
+
+// CreateTag godoc
+// @Summary      Create tag
+// @Description  Adds a tag ahead of its first use. Names are lowercased (permission: taxonomy:manage)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        input  body   taxonomy.TagRequest  true  "Tag"
+// @Success      201  {object}  models.Tag
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      409  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/tags [post]
+func (h *TaxonomyHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var input taxonomy.TagRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	created, err := h.taxonomyService.CreateTag(r.Context(), actor, input)
+	if err != nil {
+		writeTaxonomyError(w, err, "failed to create tag")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, created)
+}
+
+// RenameTag godoc
+// @Summary      Rename tag
+// @Description  Renames the tag on every news carrying it (permission: taxonomy:manage)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id     path   int                  true  "Tag ID"
+// @Param        input  body   taxonomy.TagRequest  true  "Tag"
+// @Success      200  {object}  models.Tag
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      409  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/tags/{id} [put]
+func (h *TaxonomyHandler) RenameTag(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var input taxonomy.TagRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	updated, err := h.taxonomyService.RenameTag(r.Context(), actor, id, input)
+	if err != nil {
+		writeTaxonomyError(w, err, "failed to rename tag")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, updated)
+}
+
+// DeleteTag godoc
+// @Summary      Delete tag
+// @Description  Deletes the tag and takes it off every news (permission: taxonomy:manage)
+// @Tags         admin
+// @Produce      json
+// @Param        id  path  int  true  "Tag ID"
+// @Success      200  {object}  auth.Response
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/tags/{id} [delete]
+func (h *TaxonomyHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.taxonomyService.DeleteTag(r.Context(), actor, id); err != nil {
+		writeTaxonomyError(w, err, "failed to delete tag")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "Tag deleted"})
+}
+
+func writeTaxonomyError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, errors2.ErrForbidden):
+		utils.WriteError(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, errors2.ErrValidation):
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, errors2.ErrNotFound):
+		utils.WriteError(w, http.StatusNotFound, "not found")
+	case errors.Is(err, errors2.ErrCategoryExists), errors.Is(err, errors2.ErrCategoryInUse), errors.Is(err, errors2.ErrTagExists):
+		utils.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		logger.Log.Error(message, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, message)
+	}
+}
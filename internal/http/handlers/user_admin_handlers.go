@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"news-api/internal/dto/auth"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/dto/user"
+	"news-api/internal/models"
+	"news-api/internal/service/interfaces"
+	"news-api/pkg/logger"
+	"news-api/utils"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type UserAdminHandler struct {
+	userAdminService interfaces.UserAdminService
+}
+
+func NewUserAdminHandler(userAdminService interfaces.UserAdminService) *UserAdminHandler {
+	return &UserAdminHandler{userAdminService: userAdminService}
+}
+
+// ListUsers godoc
+// @Summary      List users
+// @Description  Returns users with pagination, search and filters (permission: users:manage)
+// @Tags         admin
+// @Produce      json
+// @Param        limit      query  int     false  "Limit (default 20, max 100)"
+// @Param        offset     query  int     false  "Offset (default 0)"
+// @Param        search     query  string  false  "Search by email, first or last name"
+// @Param        role       query  string  false  "Filter by role"
+// @Param        suspended  query  bool    false  "Filter by suspension"
+// @Success      200  {object}  user.UserListResponse
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/users [get]
+func (h *UserAdminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	params := models.UserListParams{Limit: limit, Offset: offset}
+	if search := q.Get("search"); search != "" {
+		params.Search = &search
+	}
+	if role := q.Get("role"); role != "" {
+		params.Role = &role
+	}
+	if s := q.Get("suspended"); s != "" {
+		suspended, err := strconv.ParseBool(s)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, "invalid suspended filter")
+			return
+		}
+		params.Suspended = &suspended
+	}
+
+	users, total, err := h.userAdminService.ListUsers(r.Context(), actor, params)
+	if err != nil {
+		writeUserAdminError(w, err, "failed to list users")
+		return
+	}
+
+	params.Normalize()
+	resp := user.UserListResponse{
+		Items:  make([]user.UserResponse, 0, len(users)),
+		Total:  total,
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}
+	for i := range users {
+		resp.Items = append(resp.Items, userResponse(&users[i]))
+	}
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+// GetUser godoc
+// @Summary      Get user
+// @Description  Returns a single user (permission: users:manage)
+// @Tags         admin
+// @Produce      json
+// @Param        id   path   int  true  "User ID"
+// @Success      200  {object}  user.UserResponse
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/users/{id} [get]
+func (h *UserAdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	u, err := h.userAdminService.GetUser(r.Context(), actor, id)
+	if err != nil {
+		writeUserAdminError(w, err, "failed to get user")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, userResponse(u))
+}
+
+// SuspendUser godoc
+// @Summary      Suspend user
+// @Description  Blocks login, token refresh and API keys of the user and ends their sessions. Users holding privileges the caller lacks and the last active user who can manage roles cannot be suspended (permission: users:manage)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id     path   int                      true   "User ID"
+// @Param        input  body   user.SuspendUserRequest  false  "Reason"
+// @Success      200  {object}  auth.Response
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/users/{id}/suspend [post]
+func (h *UserAdminHandler) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var input user.SuspendUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	if err := h.userAdminService.SuspendUser(r.Context(), actor, id, input.Reason); err != nil {
+		writeUserAdminError(w, err, "failed to suspend user")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "User suspended"})
+}
+
+// UnsuspendUser godoc
+// @Summary      Unsuspend user
+// @Description  Lifts the suspension of the user (permission: users:manage)
+// @Tags         admin
+// @Produce      json
+// @Param        id   path   int  true  "User ID"
+// @Success      200  {object}  auth.Response
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/users/{id}/unsuspend [post]
+func (h *UserAdminHandler) UnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.userAdminService.UnsuspendUser(r.Context(), actor, id); err != nil {
+		writeUserAdminError(w, err, "failed to unsuspend user")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "User unsuspended"})
+}
+
+// DeleteUser godoc
+// @Summary      Delete user
+// @Description  Deletes the user at once. news=delete moves their news to the trash, news=anonymize keeps it without an author, news=transfer reassigns it to transfer_to (the calling admin by default). Users holding privileges the caller lacks and the last active user who can manage roles cannot be deleted (permission: users:manage)
+// @Tags         admin
+// @Produce      json
+// @Param        id           path   int     true   "User ID"
+// @Param        news         query  string  true   "What to do with the user's news"  Enums(delete, transfer, anonymize)
+// @Param        transfer_to  query  int     false  "Receiver of the news for news=transfer"
+// @Success      200  {object}  auth.Response
+// @Failure      400  {object}  errors.ErrorResponse
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      403  {object}  errors.ErrorResponse
+// @Failure      404  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/users/{id} [delete]
+func (h *UserAdminHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	input := user.DeleteUserInput{News: r.URL.Query().Get("news")}
+	if s := r.URL.Query().Get("transfer_to"); s != "" {
+		to, err := strconv.Atoi(s)
+		if err != nil || to <= 0 {
+			utils.WriteError(w, http.StatusBadRequest, "invalid transfer_to")
+			return
+		}
+		input.TransferTo = &to
+	}
+
+	if err := h.userAdminService.DeleteUser(r.Context(), actor, id, input); err != nil {
+		writeUserAdminError(w, err, "failed to delete user")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "User deleted"})
+}
+
+func userResponse(u *models.User) user.UserResponse {
+	return user.UserResponse{
+		ID:              u.ID,
+		FirstName:       u.FirstName,
+		LastName:        u.LastName,
+		Email:           u.Email,
+		Avatar:          u.Avatar,
+		Role:            u.Role,
+		EmailVerified:   u.EmailVerified(),
+		MFAEnabled:      u.MFAEnabled(),
+		SuspendedAt:     u.SuspendedAt,
+		SuspendedReason: u.SuspendedReason,
+
+		DeletionScheduledAt: u.DeletionScheduledAt,
+		CreatedAt:           u.CreatedAt,
+	}
+}
+
+func writeUserAdminError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, errors2.ErrForbidden):
+		utils.WriteError(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, errors2.ErrValidation):
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, errors2.ErrNotFound):
+		utils.WriteError(w, http.StatusNotFound, "user not found")
+	default:
+		logger.Log.Error(message, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, message)
+	}
+}
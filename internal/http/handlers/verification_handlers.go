@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"news-api/internal/dto/auth"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/service/interfaces"
+	"news-api/pkg/logger"
+	"news-api/utils"
+)
+
+type VerificationHandler struct {
+	verificationService interfaces.VerificationService
+}
+
+func NewVerificationHandler(verificationService interfaces.VerificationService) *VerificationHandler {
+	return &VerificationHandler{verificationService: verificationService}
+}
+
+// VerifyEmail godoc
+// @Summary      Verify email
+// @Description  Confirms the email address using the token from the verification email. Refresh the tokens afterwards to get an access token for a verified account.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input  body   auth.VerifyEmailInput  true  "Verification token"
+// @Success      200  {object}  auth.Response
+// @Failure      400  {object}  auth.Response
+// @Failure      409  {object}  auth.Response
+// @Failure      500  {object}  errors.ErrorResponse
+// @Router       /api/verify-email [post]
+func (h *VerificationHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var input auth.VerifyEmailInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Token == "" {
+		utils.WriteJSON(w, http.StatusBadRequest, auth.Response{Message: "Invalid request payload"})
+		return
+	}
+
+	if err := h.verificationService.VerifyEmail(r.Context(), input.Token); err != nil {
+		switch {
+		case errors.Is(err, errors2.ErrInvalidToken):
+			utils.WriteJSON(w, http.StatusBadRequest, auth.Response{Message: "Invalid or expired verification token"})
+		case errors.Is(err, errors2.ErrAccountExists):
+			utils.WriteJSON(w, http.StatusConflict, auth.Response{Message: err.Error()})
+		default:
+			logger.Log.Error("verify email failed", "error", err)
+			utils.WriteError(w, http.StatusInternalServerError, "failed to verify email")
+		}
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, auth.Response{Message: "Email verified"})
+}
+
+// ResendVerification godoc
+// @Summary      Resend verification email
+// @Description  Sends a new verification link to the current user's email
+// @Tags         auth
+// @Produce      json
+// @Success      202  {object}  auth.Response
+// @Failure      401  {object}  errors.ErrorResponse
+// @Failure      409  {object}  errors.ErrorResponse
+// @Failure      500  {object}  errors.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/verify-email/resend [post]
+func (h *VerificationHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	actor, ok := getActor(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.verificationService.ResendVerification(r.Context(), actor.UserID); err != nil {
+		switch {
+		case errors.Is(err, errors2.ErrAlreadyVerified):
+			utils.WriteError(w, http.StatusConflict, "email already verified")
+		case errors.Is(err, errors2.ErrNotFound):
+			utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		default:
+			logger.Log.Error("resend verification failed", "error", err)
+			utils.WriteError(w, http.StatusInternalServerError, "failed to send verification email")
+		}
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusAccepted, auth.Response{Message: "Verification email sent"})
+}
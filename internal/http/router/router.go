@@ -7,10 +7,35 @@ import (
 	_ "news-api/docs"
 	"news-api/internal/http/handlers"
 	"news-api/internal/middleware"
+	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	services "news-api/internal/service/interfaces"
 	"news-api/pkg/token"
 )
 
-func NewRouter(authHandler *handlers.AuthHandler, newsHandler *handlers.NewsHandler, jwtManager *token.JWTManager) *mux.Router {
+func NewRouter(
+	authHandler *handlers.AuthHandler,
+	passwordHandler *handlers.PasswordHandler,
+	verificationHandler *handlers.VerificationHandler,
+	profileHandler *handlers.ProfileHandler,
+	avatarHandler *handlers.AvatarHandler,
+	mfaHandler *handlers.MFAHandler,
+	oidcHandler *handlers.OIDCHandler,
+	magicLinkHandler *handlers.MagicLinkHandler,
+	apiKeyHandler *handlers.APIKeyHandler,
+	roleHandler *handlers.RoleHandler,
+	userAdminHandler *handlers.UserAdminHandler,
+	impersonationHandler *handlers.ImpersonationHandler,
+	auditHandler *handlers.AuditHandler,
+	newsHandler *handlers.NewsHandler,
+	taxonomyHandler *handlers.TaxonomyHandler,
+	jwksHandler *handlers.JWKSHandler,
+	mediaHandler http.Handler,
+	jwtManager *token.JWTManager,
+	denylist interfaces.TokenDenylistRepository,
+	apiKeys services.APIKeyService,
+	authz services.Authorizer,
+) *mux.Router {
 	r := mux.NewRouter()
 
 	r.Use(middleware.RecoveryMiddleware)
@@ -19,23 +44,125 @@ func NewRouter(authHandler *handlers.AuthHandler, newsHandler *handlers.NewsHand
 	r.Use(mux.CORSMethodMiddleware(r))
 
 	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
+	r.HandleFunc("/.well-known/jwks.json", jwksHandler.JWKS).Methods(http.MethodGet)
+	// Uploaded files are served here only with the local storage driver.
+	if mediaHandler != nil {
+		r.PathPrefix("/media/").Handler(http.StripPrefix("/media", mediaHandler)).Methods(http.MethodGet, http.MethodHead)
+	}
 
 	api := r.PathPrefix("/api").Subrouter()
 
 	api.HandleFunc("/register", authHandler.Register).Methods(http.MethodPost)
 	api.HandleFunc("/login", authHandler.Login).Methods(http.MethodPost)
+	api.HandleFunc("/login/mfa", authHandler.LoginMFA).Methods(http.MethodPost)
+	api.HandleFunc("/login/mfa/setup", authHandler.LoginMFASetup).Methods(http.MethodPost)
+	api.HandleFunc("/refresh", authHandler.Refresh).Methods(http.MethodPost)
+	if magicLinkHandler != nil {
+		api.HandleFunc("/login/magic", magicLinkHandler.SendLink).Methods(http.MethodPost)
+		api.HandleFunc("/login/magic/verify", magicLinkHandler.Login).Methods(http.MethodPost)
+	}
+	if oidcHandler != nil {
+		api.HandleFunc("/auth/oidc/login", oidcHandler.Login).Methods(http.MethodGet)
+		api.HandleFunc("/auth/oidc/callback", oidcHandler.Callback).Methods(http.MethodGet)
+	}
+	api.HandleFunc("/password/forgot", passwordHandler.ForgotPassword).Methods(http.MethodPost)
+	api.HandleFunc("/password/reset", passwordHandler.ResetPassword).Methods(http.MethodPost)
+	api.HandleFunc("/verify-email", verificationHandler.VerifyEmail).Methods(http.MethodPost)
 
-	api.HandleFunc("/news", newsHandler.ListNews).Methods(http.MethodGet)
-	api.HandleFunc("/news/{id:[0-9]+}", newsHandler.GetNewsByID).Methods(http.MethodGet)
+	authenticate := middleware.AuthMiddleware(jwtManager, denylist, apiKeys)
+
+	// Anyone may read published news; authors and reviewers also see drafts.
+	optionalAuth := middleware.OptionalAuth(authenticate)
+	api.Handle("/news", optionalAuth(http.HandlerFunc(newsHandler.ListNews))).Methods(http.MethodGet)
+	api.Handle("/news/{id:[0-9]+}", optionalAuth(http.HandlerFunc(newsHandler.GetNewsByID))).Methods(http.MethodGet)
+	api.HandleFunc("/categories", taxonomyHandler.ListCategories).Methods(http.MethodGet)
+	api.HandleFunc("/tags", taxonomyHandler.ListTags).Methods(http.MethodGet)
 
 	secured := api.PathPrefix("").Subrouter()
-	secured.Use(middleware.AuthMiddleware(jwtManager))
+	secured.Use(authenticate)
+
+	// Account management needs a session token; API keys only reach the
+	// content routes below.
+	account := secured.PathPrefix("").Subrouter()
+	account.Use(middleware.SessionOnly)
+
+	account.HandleFunc("/logout", authHandler.Logout).Methods(http.MethodPost)
+	account.HandleFunc("/verify-email/resend", verificationHandler.ResendVerification).Methods(http.MethodPost)
+	account.HandleFunc("/sessions", authHandler.ListSessions).Methods(http.MethodGet)
+
+	account.HandleFunc("/me", profileHandler.Me).Methods(http.MethodGet)
+
+	account.HandleFunc("/api-keys", apiKeyHandler.List).Methods(http.MethodGet)
+
+	// An admin impersonating the user may look around but neither change the
+	// account, export its data nor use admin endpoints.
+	protected := account.PathPrefix("").Subrouter()
+	protected.Use(middleware.NoImpersonation)
+
+	protected.HandleFunc("/sessions", authHandler.RevokeOtherSessions).Methods(http.MethodDelete)
+	protected.HandleFunc("/sessions/{id}", authHandler.RevokeSession).Methods(http.MethodDelete)
+
+	protected.HandleFunc("/me", profileHandler.UpdateMe).Methods(http.MethodPatch)
+	protected.HandleFunc("/me/avatar", avatarHandler.Upload).Methods(http.MethodPost)
+	protected.HandleFunc("/me/avatar", avatarHandler.Remove).Methods(http.MethodDelete)
+	protected.HandleFunc("/me/export", profileHandler.Export).Methods(http.MethodGet)
+	protected.HandleFunc("/me/password", profileHandler.ChangePassword).Methods(http.MethodPost)
+	protected.HandleFunc("/me/email", profileHandler.ChangeEmail).Methods(http.MethodPost)
+	protected.HandleFunc("/me/deletion", profileHandler.RequestDeletion).Methods(http.MethodPost)
+	protected.HandleFunc("/me/deletion", profileHandler.CancelDeletion).Methods(http.MethodDelete)
+
+	protected.HandleFunc("/mfa/setup", mfaHandler.Setup).Methods(http.MethodPost)
+	protected.HandleFunc("/mfa/enable", mfaHandler.Enable).Methods(http.MethodPost)
+	protected.HandleFunc("/mfa/disable", mfaHandler.Disable).Methods(http.MethodPost)
+	protected.HandleFunc("/mfa/recovery-codes", mfaHandler.RegenerateRecoveryCodes).Methods(http.MethodPost)
+
+	protected.HandleFunc("/api-keys", apiKeyHandler.Create).Methods(http.MethodPost)
+	protected.HandleFunc("/api-keys/{id:[0-9]+}", apiKeyHandler.Revoke).Methods(http.MethodDelete)
+
+	usersAdmin := protected.PathPrefix("/admin/users").Subrouter()
+	usersAdmin.Use(middleware.RequirePermission(authz, models.PermUsersManage))
+	usersAdmin.HandleFunc("", userAdminHandler.ListUsers).Methods(http.MethodGet)
+	usersAdmin.HandleFunc("/{id:[0-9]+}", userAdminHandler.GetUser).Methods(http.MethodGet)
+	usersAdmin.HandleFunc("/{id:[0-9]+}", userAdminHandler.DeleteUser).Methods(http.MethodDelete)
+	usersAdmin.HandleFunc("/{id:[0-9]+}/suspend", userAdminHandler.SuspendUser).Methods(http.MethodPost)
+	usersAdmin.HandleFunc("/{id:[0-9]+}/unsuspend", userAdminHandler.UnsuspendUser).Methods(http.MethodPost)
+	usersAdmin.HandleFunc("/{id:[0-9]+}/unlock", authHandler.UnlockUser).Methods(http.MethodPost)
+	usersAdmin.HandleFunc("/{id:[0-9]+}/role", roleHandler.AssignRole).Methods(http.MethodPut)
+	usersAdmin.HandleFunc("/{id:[0-9]+}/impersonate", impersonationHandler.Impersonate).Methods(http.MethodPost)
+
+	auditAdmin := protected.PathPrefix("/admin/audit").Subrouter()
+	auditAdmin.Use(middleware.RequirePermission(authz, models.PermAuditRead))
+	auditAdmin.HandleFunc("", auditHandler.ListEvents).Methods(http.MethodGet)
+
+	newsAdmin := protected.PathPrefix("/admin/news").Subrouter()
+	newsAdmin.Use(middleware.RequirePermission(authz, models.PermNewsTrash))
+	newsAdmin.HandleFunc("/trash", newsHandler.ListTrash).Methods(http.MethodGet)
+	newsAdmin.HandleFunc("/{id:[0-9]+}/restore", newsHandler.RestoreNews).Methods(http.MethodPost)
+
+	taxonomyAdmin := protected.PathPrefix("/admin").Subrouter()
+	taxonomyAdmin.Use(middleware.RequirePermission(authz, models.PermTaxonomyManage))
+	taxonomyAdmin.HandleFunc("/categories", taxonomyHandler.CreateCategory).Methods(http.MethodPost)
+	taxonomyAdmin.HandleFunc("/categories/{id:[0-9]+}", taxonomyHandler.UpdateCategory).Methods(http.MethodPut)
+	taxonomyAdmin.HandleFunc("/categories/{id:[0-9]+}", taxonomyHandler.DeleteCategory).Methods(http.MethodDelete)
+	taxonomyAdmin.HandleFunc("/tags", taxonomyHandler.CreateTag).Methods(http.MethodPost)
+	taxonomyAdmin.HandleFunc("/tags/{id:[0-9]+}", taxonomyHandler.RenameTag).Methods(http.MethodPut)
+	taxonomyAdmin.HandleFunc("/tags/{id:[0-9]+}", taxonomyHandler.DeleteTag).Methods(http.MethodDelete)
 
-	secured.HandleFunc("/logout", authHandler.Logout).Methods(http.MethodPost)
+	rolesAdmin := protected.PathPrefix("/admin").Subrouter()
+	rolesAdmin.Use(middleware.RequirePermission(authz, models.PermRolesManage))
+	rolesAdmin.HandleFunc("/permissions", roleHandler.ListPermissions).Methods(http.MethodGet)
+	rolesAdmin.HandleFunc("/roles", roleHandler.ListRoles).Methods(http.MethodGet)
+	rolesAdmin.HandleFunc("/roles", roleHandler.CreateRole).Methods(http.MethodPost)
+	rolesAdmin.HandleFunc("/roles/{name}", roleHandler.UpdateRole).Methods(http.MethodPut)
+	rolesAdmin.HandleFunc("/roles/{name}", roleHandler.DeleteRole).Methods(http.MethodDelete)
 
 	secured.HandleFunc("/news", newsHandler.CreateNews).Methods(http.MethodPost)
 	secured.HandleFunc("/news/{id:[0-9]+}", newsHandler.UpdateNews).Methods(http.MethodPut)
 	secured.HandleFunc("/news/{id:[0-9]+}", newsHandler.DeleteNews).Methods(http.MethodDelete)
+	secured.HandleFunc("/news/{id:[0-9]+}/status", newsHandler.ChangeNewsStatus).Methods(http.MethodPost)
+	secured.HandleFunc("/news/{id:[0-9]+}/revisions", newsHandler.ListNewsRevisions).Methods(http.MethodGet)
+	secured.HandleFunc("/news/{id:[0-9]+}/revisions/diff", newsHandler.DiffNewsRevisions).Methods(http.MethodGet)
+	secured.HandleFunc("/news/{id:[0-9]+}/revisions/{rev:[0-9]+}/restore", newsHandler.RestoreNewsRevision).Methods(http.MethodPost)
 
 	return r
 }
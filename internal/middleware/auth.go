@@ -2,10 +2,17 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	errors2 "news-api/internal/dto/errors"
 	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	services "news-api/internal/service/interfaces"
+	"news-api/pkg/logger"
 	"news-api/pkg/token"
 	"news-api/utils"
 )
@@ -14,9 +21,33 @@ type ctxKey string
 
 const CtxActor ctxKey = "actor"
 
-func AuthMiddleware(jwtManager *token.JWTManager) func(http.Handler) http.Handler {
+// APIKeyHeader carries an API key. Keys are also accepted as a Bearer token.
+const APIKeyHeader = "X-API-Key"
+
+// ImpersonatedByHeader is set on every response to a request made with an
+// impersonation token and names the admin behind it.
+const ImpersonatedByHeader = "X-Impersonated-By"
+
+func AuthMiddleware(jwtManager *token.JWTManager, denylist interfaces.TokenDenylistRepository, apiKeys services.APIKeyService) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key := apiKeyFrom(r); key != "" {
+				actor, err := apiKeys.Authenticate(r.Context(), key)
+				if err != nil {
+					if errors.Is(err, errors2.ErrUnauthorized) {
+						utils.WriteError(w, http.StatusUnauthorized, "invalid API key")
+						return
+					}
+					logger.Log.Error("API key check failed", "error", err)
+					utils.WriteError(w, http.StatusServiceUnavailable, "failed to validate API key")
+					return
+				}
+				actor.Client = utils.ClientInfo(r)
+				ctx := context.WithValue(r.Context(), CtxActor, *actor)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
 			authHeader := r.Header.Get("Authorization")
 			if !strings.HasPrefix(authHeader, "Bearer ") {
 				utils.WriteError(w, http.StatusUnauthorized, "missing or invalid Authorization header")
@@ -36,10 +67,129 @@ func AuthMiddleware(jwtManager *token.JWTManager) func(http.Handler) http.Handle
 				return
 			}
 			role, _ := claims["role"].(string)
+			sessionID, _ := claims["sid"].(string)
+			emailVerified, _ := claims["email_verified"].(bool)
+			tokenID, _ := claims["jti"].(string)
+			issuedAt := token.IssuedAt(claims)
+			exp, _ := claims["exp"].(float64)
+
+			revoked, err := denylist.IsRevoked(r.Context(), tokenID, sessionID, int(uidFloat), issuedAt)
+			if err != nil {
+				logger.Log.Error("token denylist check failed", "error", err)
+				utils.WriteError(w, http.StatusServiceUnavailable, "failed to validate token")
+				return
+			}
+			impersonatorID := token.ImpersonatorID(claims)
+			if !revoked && impersonatorID != 0 {
+				// Revoking all tokens of the admin ends their impersonations too.
+				revoked, err = denylist.IsRevoked(r.Context(), "", "", impersonatorID, issuedAt)
+				if err != nil {
+					logger.Log.Error("token denylist check failed", "error", err)
+					utils.WriteError(w, http.StatusServiceUnavailable, "failed to validate token")
+					return
+				}
+			}
+			if revoked {
+				utils.WriteError(w, http.StatusUnauthorized, "token revoked")
+				return
+			}
 
-			actor := models.Actor{UserID: int(uidFloat), Role: role}
+			actor := models.Actor{
+				UserID:         int(uidFloat),
+				Role:           role,
+				SessionID:      sessionID,
+				EmailVerified:  emailVerified,
+				TokenID:        tokenID,
+				TokenExpiresAt: time.Unix(int64(exp), 0),
+				ImpersonatorID: impersonatorID,
+				Client:         utils.ClientInfo(r),
+			}
+			if actor.Impersonated() {
+				w.Header().Set(ImpersonatedByHeader, strconv.Itoa(impersonatorID))
+				logger.Log.Info("impersonated request",
+					"user_id", actor.UserID, "impersonator_id", impersonatorID,
+					"method", r.Method, "path", r.URL.Path,
+				)
+			}
 			ctx := context.WithValue(r.Context(), CtxActor, actor)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// OptionalAuth runs auth for requests that carry credentials and lets the
+// others through anonymously. Public routes use it to show more to users who
+// are logged in.
+func OptionalAuth(auth func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		authed := auth(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" && apiKeyFrom(r) == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			authed.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SessionOnly refuses requests authenticated with an API key. It guards
+// account management, so that a leaked key cannot mint more keys or take
+// over the account.
+func SessionOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if actor, ok := r.Context().Value(CtxActor).(models.Actor); ok && actor.APIKeyID != 0 {
+			utils.WriteError(w, http.StatusForbidden, "API keys cannot be used for this endpoint")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// NoImpersonation refuses requests made with an impersonation token. It
+// guards admin operations and the credentials of the account, which an admin
+// acting as someone else must not touch.
+func NoImpersonation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if actor, ok := r.Context().Value(CtxActor).(models.Actor); ok && actor.Impersonated() {
+			utils.WriteError(w, http.StatusForbidden, "not allowed while impersonating a user")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequirePermission lets the request through only if the authenticated
+// actor holds the permission.
+func RequirePermission(authz services.Authorizer, permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actor, ok := r.Context().Value(CtxActor).(models.Actor)
+			if !ok {
+				utils.WriteError(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+			allowed, err := authz.Can(r.Context(), actor, permission)
+			if err != nil {
+				logger.Log.Error("permission check failed", "error", err)
+				utils.WriteError(w, http.StatusServiceUnavailable, "failed to check permissions")
+				return
+			}
+			if !allowed {
+				utils.WriteError(w, http.StatusForbidden, "forbidden")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func apiKeyFrom(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key
+	}
+	if raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && strings.HasPrefix(raw, models.APIKeyPrefix) {
+		return raw
+	}
+	return ""
+}
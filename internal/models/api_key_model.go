@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// APIKeyPrefix marks our API keys so that they can be told apart from JWTs
+// in an Authorization header.
+const APIKeyPrefix = "nak_"
+
+// Scopes an API key can be limited to. A key never allows more than the
+// role of its owner.
+const (
+	ScopeNewsCreate = "news:create"
+	ScopeNewsUpdate = "news:update"
+	ScopeNewsDelete = "news:delete"
+)
+
+var APIKeyScopes = []string{ScopeNewsCreate, ScopeNewsUpdate, ScopeNewsDelete}
+
+// APIKey is a long-lived credential for scripts. Only the hash of the key is
+// stored; Prefix is kept in clear so users can tell their keys apart.
+type APIKey struct {
+	ID         int        `db:"id"`
+	UserID     int        `db:"user_id"`
+	Name       string     `db:"name"`
+	Prefix     string     `db:"prefix"`
+	KeyHash    string     `db:"key_hash"`
+	Scopes     []string   `db:"scopes"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+func (k *APIKey) Usable(now time.Time) bool {
+	return k.RevokedAt == nil && now.Before(k.ExpiresAt)
+}
@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// Actions recorded in the audit log.
+const (
+	AuditLogin        = "auth.login"
+	AuditLogout       = "auth.logout"
+	AuditRefreshReuse = "auth.refresh_reuse"
+
+	AuditUserUnlock      = "user.unlock"
+	AuditUserSuspend     = "user.suspend"
+	AuditUserUnsuspend   = "user.unsuspend"
+	AuditUserDelete      = "user.delete"
+	AuditUserRoleChange  = "user.role_change"
+	AuditUserImpersonate = "user.impersonate"
+
+	AuditRoleCreate = "role.create"
+	AuditRoleUpdate = "role.update"
+	AuditRoleDelete = "role.delete"
+
+	AuditNewsCreate  = "news.create"
+	AuditNewsUpdate  = "news.update"
+	AuditNewsDelete  = "news.delete"
+	AuditNewsStatus  = "news.status_change"
+	AuditNewsRestore = "news.restore"
+	AuditNewsPurge   = "news.purge"
+
+	AuditCategoryCreate = "category.create"
+	AuditCategoryUpdate = "category.update"
+	AuditCategoryDelete = "category.delete"
+	AuditTagCreate      = "tag.create"
+	AuditTagUpdate      = "tag.update"
+	AuditTagDelete      = "tag.delete"
+)
+
+// What an audit event was done to.
+const (
+	AuditTargetUser     = "user"
+	AuditTargetSession  = "session"
+	AuditTargetRole     = "role"
+	AuditTargetNews     = "news"
+	AuditTargetCategory = "category"
+	AuditTargetTag      = "tag"
+)
+
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+// AuditEvent is a row of the append-only audit log. ActorID is nil for
+// events without a known user, such as a login with an unknown email or a
+// deletion carried out by the scheduler.
+type AuditEvent struct {
+	ID             int64     `db:"id"`
+	ActorID        *int      `db:"actor_id"`
+	ImpersonatorID *int      `db:"impersonator_id"`
+	Action         string    `db:"action"`
+	TargetType     string    `db:"target_type"`
+	TargetID       string    `db:"target_id"`
+	Outcome        string    `db:"outcome"`
+	Detail         string    `db:"detail"`
+	IP             string    `db:"ip"`
+	UserAgent      string    `db:"user_agent"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+type AuditListParams struct {
+	Limit      int
+	Offset     int
+	ActorID    *int
+	Action     *string
+	TargetType *string
+	TargetID   *string
+	Outcome    *string
+	From       *time.Time
+	To         *time.Time
+}
+
+func (p *AuditListParams) Normalize() {
+	if p.Limit <= 0 {
+		p.Limit = 50
+	}
+	if p.Limit > 100 {
+		p.Limit = 100
+	}
+	if p.Offset < 0 {
+		p.Offset = 0
+	}
+}
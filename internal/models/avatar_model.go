@@ -0,0 +1,22 @@
+package models
+
+// AvatarVariant is one of the square sizes an uploaded avatar is stored in.
+type AvatarVariant struct {
+	Name string
+	Size int
+}
+
+var AvatarVariants = []AvatarVariant{
+	{Name: "small", Size: 64},
+	{Name: "medium", Size: 256},
+	{Name: "large", Size: 512},
+}
+
+// DefaultAvatarVariant is the variant whose URL is kept in User.Avatar.
+const DefaultAvatarVariant = "medium"
+
+// AvatarVariantKey returns the storage key of a variant of the avatar stored
+// under base.
+func AvatarVariantKey(base, variant string) string {
+	return base + "_" + variant + ".jpg"
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// EmailVerificationToken proves ownership of Email for the user. Email may
+// differ from the current address of the user when it is being changed.
+type EmailVerificationToken struct {
+	ID        int        `db:"id"`
+	UserID    int        `db:"user_id"`
+	Email     string     `db:"email"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
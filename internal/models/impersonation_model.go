@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Impersonation records an admin acting as another user. It is written when
+// the impersonation token is issued and never changed.
+type Impersonation struct {
+	ID        int       `db:"id"`
+	AdminID   int       `db:"admin_id"`
+	UserID    int       `db:"user_id"`
+	Reason    string    `db:"reason"`
+	TokenID   string    `db:"token_id"`
+	IP        string    `db:"ip"`
+	UserAgent string    `db:"user_agent"`
+	ExpiresAt time.Time `db:"expires_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
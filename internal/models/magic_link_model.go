@@ -0,0 +1,8 @@
+package models
+
+// MagicLink is a pending passwordless login. It is bound to the email the
+// link was sent to, so that the link dies if the address changes meanwhile.
+type MagicLink struct {
+	UserID int
+	Email  string
+}
@@ -0,0 +1,30 @@
+package models
+
+const (
+	MFAPurposeLogin  = "login"
+	MFAPurposeEnroll = "enroll"
+)
+
+// MFAChallenge is the pending second step of a login after the password was
+// accepted. With MFAPurposeEnroll the user has to enrol before logging in.
+type MFAChallenge struct {
+	UserID   int
+	Purpose  string
+	Attempts int64
+}
+
+// LoginResult holds either a token pair or, when a second factor is needed,
+// the token of the MFA challenge to continue with.
+type LoginResult struct {
+	AccessToken   string
+	RefreshToken  string
+	MFAToken      string
+	MFAEnrollment bool
+	RecoveryCodes []string
+}
+
+// MFASetup is a TOTP secret waiting to be confirmed with a first code.
+type MFASetup struct {
+	Secret          string
+	ProvisioningURI string
+}
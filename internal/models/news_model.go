@@ -1,14 +1,84 @@
 package models
 
-import "time"
+import (
+	"news-api/pkg/textdiff"
+	"time"
+)
+
+// Editorial status of a news item. Only published items are public.
+// Scheduled items are approved and wait for their PublishAt.
+const (
+	NewsDraft     = "draft"
+	NewsInReview  = "in_review"
+	NewsScheduled = "scheduled"
+	NewsPublished = "published"
+	NewsArchived  = "archived"
+)
+
+var NewsStatuses = []string{NewsDraft, NewsInReview, NewsScheduled, NewsPublished, NewsArchived}
 
 type News struct {
-	ID          int       `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	AuthorID    int       `json:"author_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	AuthorID    *int       `json:"author_id"` // nil once the author deleted their account
+	Status      string     `json:"status"`
+	PublishedAt *time.Time `json:"published_at"`
+	// PublishAt is when a scheduled item gets published; UnpublishAt is when
+	// a published one is archived.
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	// CategoryIDs and Tags classify the news. On update, leaving either out
+	// keeps what the news has; an empty list clears it.
+	CategoryIDs []int    `json:"category_ids"`
+	Tags        []string `json:"tags"`
+	// DeletedAt is set while the news is in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy *int       `json:"deleted_by,omitempty"`
+}
+
+func (n *News) WrittenBy(userID int) bool {
+	return n.AuthorID != nil && *n.AuthorID == userID
+}
+
+// Public reports whether everyone may read the news at the moment. Items past
+// their UnpublishAt are hidden even before the scheduler archives them.
+func (n *News) Public(now time.Time) bool {
+	return n.Status == NewsPublished && (n.UnpublishAt == nil || now.Before(*n.UnpublishAt))
+}
+
+// NewsStatusChange asks for a news item to move to Status. PublishAt is
+// required for NewsScheduled; UnpublishAt may be given with NewsScheduled and
+// NewsPublished to archive the item later.
+type NewsStatusChange struct {
+	Status      string
+	PublishAt   *time.Time
+	UnpublishAt *time.Time
+}
+
+// NewsRevision is a saved version of the news text. RestoredFrom is set when
+// the revision brought back the text of an earlier one.
+type NewsRevision struct {
+	ID           int       `json:"-"`
+	NewsID       int       `json:"news_id"`
+	Revision     int       `json:"revision"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	EditorID     *int      `json:"editor_id"` // nil once the editor deleted their account
+	RestoredFrom *int      `json:"restored_from"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewsRevisionDiff shows line by line how the text changed from one revision
+// to another.
+type NewsRevisionDiff struct {
+	NewsID      int             `json:"news_id"`
+	From        int             `json:"from"`
+	To          int             `json:"to"`
+	Title       []textdiff.Edit `json:"title"`
+	Description []textdiff.Edit `json:"description"`
 }
 
 type NewsListParams struct {
@@ -16,6 +86,10 @@ type NewsListParams struct {
 	Offset   int
 	AuthorID *int
 	Search   *string
+	Status   *string
+	// Category is a category slug; news of its subcategories is included.
+	Category *string
+	Tag      *string
 }
 
 func (p *NewsListParams) Normalize() {
@@ -31,6 +105,37 @@ func (p *NewsListParams) Normalize() {
 }
 
 type Actor struct {
-	UserID int
-	Role   string
+	UserID         int
+	Role           string
+	SessionID      string
+	EmailVerified  bool
+	TokenID        string
+	TokenExpiresAt time.Time
+	// APIKeyID is set when the request was authenticated with an API key
+	// instead of a session token; Scopes then limit what it may do.
+	APIKeyID int
+	Scopes   []string
+	// ImpersonatorID is set when an admin acts as this user with an
+	// impersonation token.
+	ImpersonatorID int
+	// Client is where the request came from, for the audit log.
+	Client ClientInfo
+}
+
+func (a Actor) Impersonated() bool {
+	return a.ImpersonatorID != 0
+}
+
+// HasScope reports whether the credential allows the scope. Session tokens
+// are not scoped.
+func (a Actor) HasScope(scope string) bool {
+	if a.APIKeyID == 0 {
+		return true
+	}
+	for _, s := range a.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
 }
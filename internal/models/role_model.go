@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// System roles created by the migrations. New accounts get RoleEditor.
+const (
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+)
+
+// Permissions checked by the code. Which roles hold them is stored in the
+// database and can be changed by admins.
+const (
+	PermNewsCreate       = "news:create"
+	PermNewsUpdateOwn    = "news:update:own"
+	PermNewsUpdateAny    = "news:update:any"
+	PermNewsDeleteOwn    = "news:delete:own"
+	PermNewsDeleteAny    = "news:delete:any"
+	PermNewsPublish      = "news:publish"
+	PermNewsTrash        = "news:trash"
+	PermUsersManage      = "users:manage"
+	PermUsersImpersonate = "users:impersonate"
+	PermRolesManage      = "roles:manage"
+	PermAuditRead        = "audit:read"
+	PermTaxonomyManage   = "taxonomy:manage"
+)
+
+// Role is a named set of permissions. System roles are created by the
+// migrations and cannot be deleted.
+type Role struct {
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	System      bool      `db:"is_system"`
+	Permissions []string  `db:"permissions"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+type Permission struct {
+	Name        string `db:"name"`
+	Description string `db:"description"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type Session struct {
+	ID           string    `json:"id"`
+	UserID       int       `json:"user_id"`
+	RefreshToken string    `json:"-"`
+	UserAgent    string    `json:"user_agent"`
+	IP           string    `json:"ip"`
+	CreatedAt    time.Time `json:"created_at"`
+	LastUsedAt   time.Time `json:"last_used_at"`
+}
+
+// ClientInfo describes the device a request came from.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Category is a section of the site. Categories form a tree through
+// ParentID; listing news by a category includes its subcategories.
+type Category struct {
+	ID          int       `json:"id"`
+	ParentID    *int      `json:"parent_id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Tag is a free-form label. Tags are created when first put on a news item
+// and their names are kept lowercased.
+type Tag struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	NewsCount int       `json:"news_count"`
+	CreatedAt time.Time `json:"created_at"`
+}
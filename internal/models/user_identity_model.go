@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// UserIdentity links a user to an account at an external identity provider.
+// Provider is the issuer URL, Subject the stable user id at that issuer.
+type UserIdentity struct {
+	ID          int        `db:"id"`
+	UserID      int        `db:"user_id"`
+	Provider    string     `db:"provider"`
+	Subject     string     `db:"subject"`
+	Email       string     `db:"email"`
+	CreatedAt   time.Time  `db:"created_at"`
+	LastLoginAt *time.Time `db:"last_login_at"`
+}
+
+// OIDCState is what we remember between redirecting to the identity
+// provider and its callback.
+type OIDCState struct {
+	CodeVerifier string
+	Nonce        string
+}
@@ -11,4 +11,60 @@ type User struct {
 	Role      string    `db:"role"`
 	Avatar    string    `db:"avatar,omitempty"`
 	CreatedAt time.Time `db:"created_at"`
+
+	EmailVerifiedAt *time.Time `db:"email_verified_at"`
+	MFASecret       string     `db:"mfa_secret"`
+	MFAEnabledAt    *time.Time `db:"mfa_enabled_at"`
+	SuspendedAt     *time.Time `db:"suspended_at"`
+	SuspendedReason string     `db:"suspended_reason"`
+	AvatarKey       string     `db:"avatar_key"`
+
+	DeletionScheduledAt *time.Time `db:"deletion_scheduled_at"`
+}
+
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) MFAEnabled() bool {
+	return u.MFAEnabledAt != nil
+}
+
+func (u *User) Suspended() bool {
+	return u.SuspendedAt != nil
+}
+
+// What happens to the news of a deleted user.
+const (
+	NewsDelete    = "delete"
+	NewsTransfer  = "transfer"
+	NewsAnonymize = "anonymize"
+)
+
+// UserDataExport is everything stored about a user, for the user to take.
+type UserDataExport struct {
+	User       *User
+	News       []News
+	Sessions   []Session
+	ExportedAt time.Time
+}
+
+type UserListParams struct {
+	Limit     int
+	Offset    int
+	Search    *string
+	Role      *string
+	Suspended *bool
+}
+
+func (p *UserListParams) Normalize() {
+	if p.Limit <= 0 {
+		p.Limit = 20
+	}
+	if p.Limit > 100 {
+		p.Limit = 100
+	}
+	if p.Offset < 0 {
+		p.Offset = 0
+	}
 }
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"news-api/internal/models"
+	"news-api/pkg/logger"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at`
+
+type APIKeyRepository struct {
+	DB *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{DB: db}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	query := `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	err := r.DB.QueryRowContext(ctx, query,
+		key.UserID, key.Name, key.Prefix, key.KeyHash, pq.Array(key.Scopes), key.ExpiresAt,
+	).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		logger.Log.Error("Error creating API key", "error", err)
+		return err
+	}
+	return nil
+}
+
+// GetByHash returns the key with the hash, including revoked and expired
+// ones, or nil if there is none.
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash=$1`
+	key, err := scanAPIKey(r.DB.QueryRowContext(ctx, query, keyHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		logger.Log.Error("Error fetching API key", "error", err)
+		return nil, err
+	}
+	return key, nil
+}
+
+// ListByUser returns the keys of the user that are not revoked, newest first.
+func (r *APIKeyRepository) ListByUser(ctx context.Context, userID int) ([]models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE user_id=$1 AND revoked_at IS NULL ORDER BY created_at DESC`
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		logger.Log.Error("Error listing API keys", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []models.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			logger.Log.Error("Error scanning API key", "error", err)
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	return keys, rows.Err()
+}
+
+// CountActive counts the keys of the user that can still be used.
+func (r *APIKeyRepository) CountActive(ctx context.Context, userID int) (int, error) {
+	var n int
+	query := `SELECT COUNT(*) FROM api_keys WHERE user_id=$1 AND revoked_at IS NULL AND expires_at > NOW()`
+	if err := r.DB.QueryRowContext(ctx, query, userID).Scan(&n); err != nil {
+		logger.Log.Error("Error counting API keys", "error", err)
+		return 0, err
+	}
+	return n, nil
+}
+
+// Revoke revokes the key if it belongs to the user. It returns false if the
+// user has no such active key.
+func (r *APIKeyRepository) Revoke(ctx context.Context, userID, id int) (bool, error) {
+	query := `UPDATE api_keys SET revoked_at=NOW() WHERE id=$1 AND user_id=$2 AND revoked_at IS NULL`
+	res, err := r.DB.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		logger.Log.Error("Error revoking API key", "error", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// TouchLastUsed records a use of the key. Writes are skipped while the last
+// recorded use is more recent than precision, so busy keys do not cause a
+// write on every request.
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id int, precision time.Duration) error {
+	query := `
+		UPDATE api_keys SET last_used_at=NOW()
+		WHERE id=$1 AND (last_used_at IS NULL OR last_used_at < NOW() - $2 * INTERVAL '1 second')
+	`
+	if _, err := r.DB.ExecContext(ctx, query, id, int(precision.Seconds())); err != nil {
+		logger.Log.Error("Error updating API key last use", "error", err)
+		return err
+	}
+	return nil
+}
+
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	key := &models.APIKey{}
+	err := row.Scan(
+		&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.KeyHash, pq.Array(&key.Scopes),
+		&key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
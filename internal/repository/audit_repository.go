@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"news-api/internal/models"
+	"news-api/pkg/logger"
+)
+
+// AuditRepository only ever inserts; the table refuses updates and deletes.
+type AuditRepository struct {
+	DB *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{DB: db}
+}
+
+const auditColumns = `id, actor_id, impersonator_id, action, target_type, target_id, outcome, detail, ip, user_agent, created_at`
+
+func (r *AuditRepository) Create(ctx context.Context, e *models.AuditEvent) error {
+	query := `
+		INSERT INTO audit_events (actor_id, impersonator_id, action, target_type, target_id, outcome, detail, ip, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`
+	err := r.DB.QueryRowContext(ctx, query,
+		e.ActorID, e.ImpersonatorID, e.Action, e.TargetType, e.TargetID, e.Outcome, e.Detail, e.IP, e.UserAgent,
+	).Scan(&e.ID, &e.CreatedAt)
+	if err != nil {
+		logger.Log.Error("Error recording audit event", "error", err)
+		return err
+	}
+	return nil
+}
+
+// List returns a page of events matching the filters, newest first, and the
+// total number of matching events.
+func (r *AuditRepository) List(ctx context.Context, params models.AuditListParams) ([]models.AuditEvent, int, error) {
+	where := ` WHERE 1=1`
+	args := []interface{}{}
+	argPos := 1
+
+	filter := func(column string, value interface{}) {
+		where += fmt.Sprintf(" AND %s $%d", column, argPos)
+		args = append(args, value)
+		argPos++
+	}
+	if params.ActorID != nil {
+		filter("actor_id =", *params.ActorID)
+	}
+	if params.Action != nil {
+		filter("action =", *params.Action)
+	}
+	if params.TargetType != nil {
+		filter("target_type =", *params.TargetType)
+	}
+	if params.TargetID != nil {
+		filter("target_id =", *params.TargetID)
+	}
+	if params.Outcome != nil {
+		filter("outcome =", *params.Outcome)
+	}
+	if params.From != nil {
+		filter("created_at >=", *params.From)
+	}
+	if params.To != nil {
+		filter("created_at <", *params.To)
+	}
+
+	var total int
+	if err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_events`+where, args...).Scan(&total); err != nil {
+		logger.Log.Error("Error counting audit events", "error", err)
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + auditColumns + ` FROM audit_events` + where +
+		fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	args = append(args, params.Limit, params.Offset)
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Log.Error("Error listing audit events", "error", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	events := []models.AuditEvent{}
+	for rows.Next() {
+		var e models.AuditEvent
+		if err := rows.Scan(
+			&e.ID, &e.ActorID, &e.ImpersonatorID, &e.Action, &e.TargetType, &e.TargetID,
+			&e.Outcome, &e.Detail, &e.IP, &e.UserAgent, &e.CreatedAt,
+		); err != nil {
+			logger.Log.Error("Error scanning audit event row", "error", err)
+			return nil, 0, err
+		}
+		events = append(events, e)
+	}
+	return events, total, rows.Err()
+}
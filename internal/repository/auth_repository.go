@@ -4,10 +4,19 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"news-api/internal/models"
 	"news-api/pkg/logger"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
 )
 
+const userColumns = `id, first_name, last_name, email, password, role, COALESCE(avatar, ''), email_verified_at,
+	COALESCE(mfa_secret, ''), mfa_enabled_at, suspended_at, COALESCE(suspended_reason, ''),
+	COALESCE(avatar_key, ''), deletion_scheduled_at, created_at`
+
 type UserRepository struct {
 	DB *sql.DB
 }
@@ -38,16 +47,13 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 
 func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 	user := &models.User{}
-	query := `SELECT id, first_name, last_name, email, password, role, avatar, created_at FROM users WHERE email=$1`
-	err := r.DB.QueryRow(query, email).Scan(
-		&user.ID, &user.FirstName, &user.LastName,
-		&user.Email, &user.Password, &user.Role, &user.Avatar, &user.CreatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE email=$1`
+	err := scanUser(r.DB.QueryRow(query, email), user)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
-		logger.Log.Error("Error fetching user by email", err)
+		logger.Log.Error("Error fetching user by email", "error", err)
 		return nil, err
 	}
 	return user, nil
@@ -55,17 +61,260 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 
 func (r *UserRepository) GetByID(id int) (*models.User, error) {
 	user := &models.User{}
-	query := `SELECT id, first_name, last_name, email, password, role, avatar, created_at FROM users WHERE id=$1`
-	err := r.DB.QueryRow(query, id).Scan(
-		&user.ID, &user.FirstName, &user.LastName,
-		&user.Email, &user.Password, &user.Role, &user.Avatar, &user.CreatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE id=$1`
+	err := scanUser(r.DB.QueryRow(query, id), user)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
-		logger.Log.Error("Error fetching user by id", err)
+		logger.Log.Error("Error fetching user by id", "error", err)
 		return nil, err
 	}
 	return user, nil
 }
+
+func (r *UserRepository) UpdateProfile(ctx context.Context, userID int, firstName, lastName string) error {
+	query := `UPDATE users SET first_name=$1, last_name=$2 WHERE id=$3`
+	if _, err := r.DB.ExecContext(ctx, query, firstName, lastName, userID); err != nil {
+		logger.Log.Error("Error updating user profile", "error", err)
+		return err
+	}
+	return nil
+}
+
+// SetAvatar stores the avatar URL and, for uploaded avatars, its storage key.
+// Empty values clear the avatar.
+func (r *UserRepository) SetAvatar(ctx context.Context, userID int, url, key string) error {
+	query := `UPDATE users SET avatar=NULLIF($1, ''), avatar_key=NULLIF($2, '') WHERE id=$3`
+	if _, err := r.DB.ExecContext(ctx, query, url, key, userID); err != nil {
+		logger.Log.Error("Error updating user avatar", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, userID int, passwordHash string) error {
+	query := `UPDATE users SET password=$1 WHERE id=$2`
+	if _, err := r.DB.ExecContext(ctx, query, passwordHash, userID); err != nil {
+		logger.Log.Error("Error updating user password", "error", err)
+		return err
+	}
+	return nil
+}
+
+// MarkEmailVerified sets the email of the user and marks it as verified.
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, userID int, email string) error {
+	query := `UPDATE users SET email=$1, email_verified_at=NOW() WHERE id=$2`
+	if _, err := r.DB.ExecContext(ctx, query, email, userID); err != nil {
+		logger.Log.Error("Error marking email verified", "error", err)
+		return err
+	}
+	return nil
+}
+
+// SetMFASecret stores a new, not yet confirmed TOTP secret. MFA stays off
+// until EnableMFA is called.
+func (r *UserRepository) SetMFASecret(ctx context.Context, userID int, secret string) error {
+	query := `UPDATE users SET mfa_secret=$1, mfa_enabled_at=NULL WHERE id=$2`
+	if _, err := r.DB.ExecContext(ctx, query, secret, userID); err != nil {
+		logger.Log.Error("Error saving MFA secret", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *UserRepository) EnableMFA(ctx context.Context, userID int) error {
+	query := `UPDATE users SET mfa_enabled_at=NOW() WHERE id=$1 AND mfa_secret IS NOT NULL`
+	if _, err := r.DB.ExecContext(ctx, query, userID); err != nil {
+		logger.Log.Error("Error enabling MFA", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *UserRepository) DisableMFA(ctx context.Context, userID int) error {
+	query := `UPDATE users SET mfa_secret=NULL, mfa_enabled_at=NULL WHERE id=$1`
+	if _, err := r.DB.ExecContext(ctx, query, userID); err != nil {
+		logger.Log.Error("Error disabling MFA", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *UserRepository) UpdateRole(ctx context.Context, userID int, role string) error {
+	query := `UPDATE users SET role=$1 WHERE id=$2`
+	if _, err := r.DB.ExecContext(ctx, query, role, userID); err != nil {
+		logger.Log.Error("Error updating user role", "error", err)
+		return err
+	}
+	return nil
+}
+
+// CountActiveInRoles counts the users with one of the roles that are not
+// suspended.
+func (r *UserRepository) CountActiveInRoles(ctx context.Context, roles []string) (int, error) {
+	var n int
+	query := `SELECT COUNT(*) FROM users WHERE role = ANY($1) AND suspended_at IS NULL`
+	if err := r.DB.QueryRowContext(ctx, query, pq.Array(roles)).Scan(&n); err != nil {
+		logger.Log.Error("Error counting users in roles", "error", err)
+		return 0, err
+	}
+	return n, nil
+}
+
+// List returns a page of users matching the filters and the total number of
+// matching users.
+func (r *UserRepository) List(ctx context.Context, params models.UserListParams) ([]models.User, int, error) {
+	where := ` WHERE 1=1`
+	args := []interface{}{}
+	argPos := 1
+
+	if params.Search != nil && strings.TrimSpace(*params.Search) != "" {
+		where += fmt.Sprintf(" AND (email ILIKE $%d OR first_name ILIKE $%d OR last_name ILIKE $%d)", argPos, argPos, argPos)
+		args = append(args, "%"+strings.TrimSpace(*params.Search)+"%")
+		argPos++
+	}
+	if params.Role != nil {
+		where += fmt.Sprintf(" AND role=$%d", argPos)
+		args = append(args, *params.Role)
+		argPos++
+	}
+	if params.Suspended != nil {
+		if *params.Suspended {
+			where += " AND suspended_at IS NOT NULL"
+		} else {
+			where += " AND suspended_at IS NULL"
+		}
+	}
+
+	var total int
+	if err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`+where, args...).Scan(&total); err != nil {
+		logger.Log.Error("Error counting users", "error", err)
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users` + where +
+		fmt.Sprintf(" ORDER BY id LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	args = append(args, params.Limit, params.Offset)
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Log.Error("Error listing users", "error", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var u models.User
+		if err := scanUser(rows, &u); err != nil {
+			logger.Log.Error("Error scanning user row", "error", err)
+			return nil, 0, err
+		}
+		users = append(users, u)
+	}
+	return users, total, rows.Err()
+}
+
+// Suspend blocks the user; Unsuspend lifts it.
+func (r *UserRepository) Suspend(ctx context.Context, userID int, reason string) error {
+	query := `UPDATE users SET suspended_at=NOW(), suspended_reason=$1 WHERE id=$2`
+	if _, err := r.DB.ExecContext(ctx, query, reason, userID); err != nil {
+		logger.Log.Error("Error suspending user", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *UserRepository) Unsuspend(ctx context.Context, userID int) error {
+	query := `UPDATE users SET suspended_at=NULL, suspended_reason=NULL WHERE id=$1`
+	if _, err := r.DB.ExecContext(ctx, query, userID); err != nil {
+		logger.Log.Error("Error unsuspending user", "error", err)
+		return err
+	}
+	return nil
+}
+
+// Delete removes the user. newsAction is one of models.NewsDelete,
+// NewsTransfer (to transferTo) or NewsAnonymize and is applied to the news
+// of the user in the same transaction. Deleted news goes to the trash, left
+// without an author, and is purged with the rest of it.
+func (r *UserRepository) Delete(ctx context.Context, userID int, newsAction string, transferTo int) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Log.Error("Error starting transaction", "error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var query string
+	args := []interface{}{userID}
+	switch newsAction {
+	case models.NewsDelete:
+		query = `UPDATE news SET deleted_at=NOW() WHERE author_id=$1 AND deleted_at IS NULL`
+	case models.NewsTransfer:
+		query = `UPDATE news SET author_id=$2 WHERE author_id=$1`
+		args = append(args, transferTo)
+	case models.NewsAnonymize:
+		query = `UPDATE news SET author_id=NULL WHERE author_id=$1`
+	default:
+		return fmt.Errorf("unknown news action %q", newsAction)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		logger.Log.Error("Error handling news of deleted user", "error", err, "action", newsAction)
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id=$1`, userID); err != nil {
+		logger.Log.Error("Error deleting user", "error", err)
+		return err
+	}
+	return tx.Commit()
+}
+
+// ScheduleDeletion sets when the account is to be deleted; nil cancels a
+// scheduled deletion.
+func (r *UserRepository) ScheduleDeletion(ctx context.Context, userID int, at *time.Time) error {
+	query := `UPDATE users SET deletion_scheduled_at=$1 WHERE id=$2`
+	if _, err := r.DB.ExecContext(ctx, query, at, userID); err != nil {
+		logger.Log.Error("Error scheduling user deletion", "error", err)
+		return err
+	}
+	return nil
+}
+
+// ListDueForDeletion returns up to limit users whose deletion is due.
+func (r *UserRepository) ListDueForDeletion(ctx context.Context, now time.Time, limit int) ([]models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users
+		WHERE deletion_scheduled_at <= $1 ORDER BY deletion_scheduled_at LIMIT $2`
+	rows, err := r.DB.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		logger.Log.Error("Error listing users due for deletion", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var u models.User
+		if err := scanUser(rows, &u); err != nil {
+			logger.Log.Error("Error scanning user row", "error", err)
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row rowScanner, user *models.User) error {
+	return row.Scan(
+		&user.ID, &user.FirstName, &user.LastName,
+		&user.Email, &user.Password, &user.Role, &user.Avatar,
+		&user.EmailVerifiedAt, &user.MFASecret, &user.MFAEnabledAt,
+		&user.SuspendedAt, &user.SuspendedReason, &user.AvatarKey,
+		&user.DeletionScheduledAt, &user.CreatedAt,
+	)
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"news-api/internal/models"
+	"news-api/pkg/logger"
+)
+
+type EmailVerificationRepository struct {
+	DB *sql.DB
+}
+
+func NewEmailVerificationRepository(db *sql.DB) *EmailVerificationRepository {
+	return &EmailVerificationRepository{DB: db}
+}
+
+func (r *EmailVerificationRepository) Create(ctx context.Context, t *models.EmailVerificationToken) error {
+	query := `
+		INSERT INTO email_verification_tokens (user_id, email, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	err := r.DB.QueryRowContext(ctx, query, t.UserID, t.Email, t.TokenHash, t.ExpiresAt).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		logger.Log.Error("Error creating email verification token", "error", err)
+		return err
+	}
+	return nil
+}
+
+// Consume marks an unused, unexpired token as used and returns it, or nil if
+// no such token exists.
+func (r *EmailVerificationRepository) Consume(ctx context.Context, tokenHash string) (*models.EmailVerificationToken, error) {
+	t := &models.EmailVerificationToken{}
+	query := `
+		UPDATE email_verification_tokens
+		SET used_at=NOW()
+		WHERE token_hash=$1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING id, user_id, email, token_hash, expires_at, used_at, created_at
+	`
+	err := r.DB.QueryRowContext(ctx, query, tokenHash).Scan(
+		&t.ID, &t.UserID, &t.Email, &t.TokenHash, &t.ExpiresAt, &t.UsedAt, &t.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		logger.Log.Error("Error consuming email verification token", "error", err)
+		return nil, err
+	}
+	return t, nil
+}
+
+// InvalidateByUser marks every outstanding token of the user as used.
+func (r *EmailVerificationRepository) InvalidateByUser(ctx context.Context, userID int) error {
+	query := `UPDATE email_verification_tokens SET used_at=NOW() WHERE user_id=$1 AND used_at IS NULL`
+	if _, err := r.DB.ExecContext(ctx, query, userID); err != nil {
+		logger.Log.Error("Error invalidating email verification tokens", "error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"news-api/internal/models"
+	"news-api/pkg/logger"
+)
+
+type ImpersonationRepository struct {
+	DB *sql.DB
+}
+
+func NewImpersonationRepository(db *sql.DB) *ImpersonationRepository {
+	return &ImpersonationRepository{DB: db}
+}
+
+func (r *ImpersonationRepository) Create(ctx context.Context, imp *models.Impersonation) error {
+	query := `
+		INSERT INTO impersonations (admin_id, user_id, reason, token_id, ip, user_agent, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	err := r.DB.QueryRowContext(ctx, query,
+		imp.AdminID, imp.UserID, imp.Reason, imp.TokenID, imp.IP, imp.UserAgent, imp.ExpiresAt,
+	).Scan(&imp.ID, &imp.CreatedAt)
+	if err != nil {
+		logger.Log.Error("Error recording impersonation", "error", err)
+		return err
+	}
+	return nil
+}
@@ -3,18 +3,156 @@ package interfaces
 import (
 	"context"
 	"news-api/internal/models"
+	"time"
 )
 
 type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
 	GetByEmail(email string) (*models.User, error)
 	GetByID(id int) (*models.User, error)
+	UpdateProfile(ctx context.Context, userID int, firstName, lastName string) error
+	SetAvatar(ctx context.Context, userID int, url, key string) error
+	UpdatePassword(ctx context.Context, userID int, passwordHash string) error
+	MarkEmailVerified(ctx context.Context, userID int, email string) error
+	SetMFASecret(ctx context.Context, userID int, secret string) error
+	EnableMFA(ctx context.Context, userID int) error
+	DisableMFA(ctx context.Context, userID int) error
+	UpdateRole(ctx context.Context, userID int, role string) error
+	CountActiveInRoles(ctx context.Context, roles []string) (int, error)
+	List(ctx context.Context, params models.UserListParams) ([]models.User, int, error)
+	Suspend(ctx context.Context, userID int, reason string) error
+	Unsuspend(ctx context.Context, userID int) error
+	Delete(ctx context.Context, userID int, newsAction string, transferTo int) error
+	ScheduleDeletion(ctx context.Context, userID int, at *time.Time) error
+	ListDueForDeletion(ctx context.Context, now time.Time, limit int) ([]models.User, error)
+}
+
+type RoleRepository interface {
+	List(ctx context.Context) ([]models.Role, error)
+	Get(ctx context.Context, name string) (*models.Role, error)
+	Create(ctx context.Context, role *models.Role) error
+	Update(ctx context.Context, role *models.Role) error
+	Delete(ctx context.Context, name string) error
+	CountUsers(ctx context.Context, name string) (int, error)
+	ListPermissions(ctx context.Context) ([]models.Permission, error)
+}
+
+type TaxonomyRepository interface {
+	ListCategories(ctx context.Context) ([]models.Category, error)
+	GetCategory(ctx context.Context, id int) (*models.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, error)
+	CreateCategory(ctx context.Context, c *models.Category) error
+	UpdateCategory(ctx context.Context, c *models.Category) error
+	DeleteCategory(ctx context.Context, id int) error
+	CountCategories(ctx context.Context, ids []int) (int, error)
+	ListTags(ctx context.Context) ([]models.Tag, error)
+	GetTag(ctx context.Context, id int) (*models.Tag, error)
+	GetTagByName(ctx context.Context, name string) (*models.Tag, error)
+	CreateTag(ctx context.Context, t *models.Tag) error
+	RenameTag(ctx context.Context, id int, name string) error
+	DeleteTag(ctx context.Context, id int) error
+}
+
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *models.UserIdentity) error
+	Get(ctx context.Context, provider, subject string) (*models.UserIdentity, error)
+	TouchLogin(ctx context.Context, id int, email string) error
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	ListByUser(ctx context.Context, userID int) ([]models.APIKey, error)
+	CountActive(ctx context.Context, userID int) (int, error)
+	Revoke(ctx context.Context, userID, id int) (bool, error)
+	TouchLastUsed(ctx context.Context, id int, precision time.Duration) error
+}
+
+type ImpersonationRepository interface {
+	Create(ctx context.Context, imp *models.Impersonation) error
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, e *models.AuditEvent) error
+	List(ctx context.Context, params models.AuditListParams) ([]models.AuditEvent, int, error)
+}
+
+type OIDCStateRepository interface {
+	Save(ctx context.Context, stateHash string, state models.OIDCState, ttl time.Duration) error
+	Consume(ctx context.Context, stateHash string) (*models.OIDCState, error)
+}
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, t *models.PasswordResetToken, cooldown time.Duration) (bool, error)
+	Get(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error)
+	Consume(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error)
+	InvalidateByUser(ctx context.Context, userID int) error
 }
 
 type NewsRepository interface {
 	Create(news *models.News) error
-	Update(news *models.News) error
-	Delete(id int) error
+	Update(news *models.News, rev *models.NewsRevision) error
+	UpdateStatus(news *models.News, from string) (bool, error)
+	ApplySchedule(ctx context.Context) (published, archived []int, err error)
+	Delete(id, deletedBy int) error
+	Restore(id int) (*models.News, error)
+	PurgeDeleted(ctx context.Context, before time.Time) ([]int, error)
 	GetByID(id int) (*models.News, error)
 	List(params models.NewsListParams) ([]models.News, error)
+	ListDeleted(params models.NewsListParams) ([]models.News, int, error)
+	ListByAuthor(authorID int) ([]models.News, error)
+	ListRevisions(newsID int) ([]models.NewsRevision, error)
+	GetRevision(newsID, revision int) (*models.NewsRevision, error)
+}
+
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, t *models.EmailVerificationToken) error
+	Consume(ctx context.Context, tokenHash string) (*models.EmailVerificationToken, error)
+	InvalidateByUser(ctx context.Context, userID int) error
+}
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *models.Session, ttl time.Duration) error
+	GetByID(ctx context.Context, id string) (*models.Session, error)
+	Rotate(ctx context.Context, session *models.Session, oldRefresh string, ttl time.Duration) (bool, error)
+	ListByUser(ctx context.Context, userID int) ([]models.Session, error)
+	Delete(ctx context.Context, userID int, id string) error
+	DeleteByUser(ctx context.Context, userID int, keepID string) ([]string, error)
+}
+
+type TokenDenylistRepository interface {
+	RevokeToken(ctx context.Context, jti string, ttl time.Duration) error
+	RevokeSessions(ctx context.Context, sessionIDs []string, ttl time.Duration) error
+	RevokeUser(ctx context.Context, userID int, before time.Time, ttl time.Duration) error
+	IsRevoked(ctx context.Context, jti, sessionID string, userID int, issuedAt time.Time) (bool, error)
+}
+
+type LoginAttemptRepository interface {
+	RecordFailure(ctx context.Context, email, ip string, window time.Duration) (int64, int64, error)
+	BlockEmail(ctx context.Context, email string, d time.Duration) error
+	BlockIP(ctx context.Context, ip string, d time.Duration) error
+	LockEmail(ctx context.Context, email string, d time.Duration) error
+	LockedFor(ctx context.Context, email string) (time.Duration, error)
+	BlockedFor(ctx context.Context, email, ip string) (time.Duration, error)
+	Reset(ctx context.Context, email string) error
+}
+
+type RecoveryCodeRepository interface {
+	Replace(ctx context.Context, userID int, codeHashes []string) error
+	Consume(ctx context.Context, userID int, codeHash string) (bool, error)
+	DeleteByUser(ctx context.Context, userID int) error
+}
+
+type MagicLinkRepository interface {
+	Save(ctx context.Context, nonce string, link models.MagicLink, ttl time.Duration) error
+	Consume(ctx context.Context, nonce string) (*models.MagicLink, error)
+	StartCooldown(ctx context.Context, userID int, cooldown time.Duration) (bool, error)
+}
+
+type MFAChallengeRepository interface {
+	Create(ctx context.Context, tokenHash string, challenge models.MFAChallenge, ttl time.Duration) error
+	Get(ctx context.Context, tokenHash string) (*models.MFAChallenge, error)
+	AddAttempt(ctx context.Context, tokenHash string) (int64, error)
+	Delete(ctx context.Context, tokenHash string) error
+	ClaimStep(ctx context.Context, userID int, step int64, ttl time.Duration) (bool, error)
 }
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"news-api/pkg/logger"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LoginAttemptRepository tracks failed logins per email and per IP in Redis.
+// A block delays the next attempt (backoff); a lock refuses logins for an
+// email until it expires or an admin removes it.
+type LoginAttemptRepository struct {
+	Redis *redis.Client
+}
+
+func NewLoginAttemptRepository(client *redis.Client) *LoginAttemptRepository {
+	return &LoginAttemptRepository{Redis: client}
+}
+
+// RecordFailure counts a failed login and returns the number of failures for
+// the email and for the IP within the window.
+func (r *LoginAttemptRepository) RecordFailure(ctx context.Context, email, ip string, window time.Duration) (int64, int64, error) {
+	emailFails, err := r.incr(ctx, loginFailKey("email", email), window)
+	if err != nil {
+		return 0, 0, err
+	}
+	ipFails, err := r.incr(ctx, loginFailKey("ip", ip), window)
+	if err != nil {
+		return 0, 0, err
+	}
+	return emailFails, ipFails, nil
+}
+
+func (r *LoginAttemptRepository) BlockEmail(ctx context.Context, email string, d time.Duration) error {
+	return r.set(ctx, loginBlockKey("email", email), d)
+}
+
+func (r *LoginAttemptRepository) BlockIP(ctx context.Context, ip string, d time.Duration) error {
+	return r.set(ctx, loginBlockKey("ip", ip), d)
+}
+
+func (r *LoginAttemptRepository) LockEmail(ctx context.Context, email string, d time.Duration) error {
+	return r.set(ctx, loginLockKey(email), d)
+}
+
+// LockedFor returns how long logins for the email stay locked, or zero.
+func (r *LoginAttemptRepository) LockedFor(ctx context.Context, email string) (time.Duration, error) {
+	return r.ttl(ctx, loginLockKey(email))
+}
+
+// BlockedFor returns how long the caller has to wait before the next attempt
+// for the email or from the IP, whichever is longer.
+func (r *LoginAttemptRepository) BlockedFor(ctx context.Context, email, ip string) (time.Duration, error) {
+	byEmail, err := r.ttl(ctx, loginBlockKey("email", email))
+	if err != nil {
+		return 0, err
+	}
+	byIP, err := r.ttl(ctx, loginBlockKey("ip", ip))
+	if err != nil {
+		return 0, err
+	}
+	return max(byEmail, byIP), nil
+}
+
+// Reset forgets failures, blocks and locks of the email.
+func (r *LoginAttemptRepository) Reset(ctx context.Context, email string) error {
+	err := r.Redis.Del(ctx, loginFailKey("email", email), loginBlockKey("email", email), loginLockKey(email)).Err()
+	if err != nil {
+		logger.Log.Error("Error resetting login attempts", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *LoginAttemptRepository) incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	n, err := r.Redis.Incr(ctx, key).Result()
+	if err != nil {
+		logger.Log.Error("Error counting login failure", "error", err)
+		return 0, err
+	}
+	if n == 1 {
+		if err := r.Redis.Expire(ctx, key, window).Err(); err != nil {
+			logger.Log.Error("Error setting login failure window", "error", err)
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+func (r *LoginAttemptRepository) set(ctx context.Context, key string, d time.Duration) error {
+	if err := r.Redis.Set(ctx, key, 1, d).Err(); err != nil {
+		logger.Log.Error("Error saving login penalty", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *LoginAttemptRepository) ttl(ctx context.Context, key string) (time.Duration, error) {
+	d, err := r.Redis.PTTL(ctx, key).Result()
+	if err != nil {
+		logger.Log.Error("Error reading login penalty", "error", err)
+		return 0, err
+	}
+	if d < 0 {
+		return 0, nil
+	}
+	return d, nil
+}
+
+func loginFailKey(scope, value string) string {
+	return "login_fail:" + scope + ":" + value
+}
+
+func loginBlockKey(scope, value string) string {
+	return "login_block:" + scope + ":" + value
+}
+
+func loginLockKey(email string) string {
+	return "login_lock:email:" + email
+}
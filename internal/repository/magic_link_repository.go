@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"news-api/internal/models"
+	"news-api/pkg/logger"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// MagicLinkRepository keeps the nonces of sent login links in Redis until
+// they are redeemed or expire.
+type MagicLinkRepository struct {
+	Redis *redis.Client
+}
+
+func NewMagicLinkRepository(client *redis.Client) *MagicLinkRepository {
+	return &MagicLinkRepository{Redis: client}
+}
+
+func (r *MagicLinkRepository) Save(ctx context.Context, nonce string, link models.MagicLink, ttl time.Duration) error {
+	key := magicLinkKey(nonce)
+	pipe := r.Redis.TxPipeline()
+	pipe.HSet(ctx, key, "user_id", link.UserID, "email", link.Email)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Log.Error("Error saving magic link", "error", err)
+		return err
+	}
+	return nil
+}
+
+// Consume returns and deletes the link, so that it can be used only once. It
+// returns nil if the nonce is unknown, used or expired.
+func (r *MagicLinkRepository) Consume(ctx context.Context, nonce string) (*models.MagicLink, error) {
+	key := magicLinkKey(nonce)
+	pipe := r.Redis.TxPipeline()
+	get := pipe.HGetAll(ctx, key)
+	pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Log.Error("Error consuming magic link", "error", err)
+		return nil, err
+	}
+
+	values := get.Val()
+	if len(values) == 0 {
+		return nil, nil
+	}
+	userID, _ := strconv.Atoi(values["user_id"])
+	return &models.MagicLink{UserID: userID, Email: values["email"]}, nil
+}
+
+// StartCooldown reports whether another link may be sent to the user now and,
+// if so, blocks further ones for the given time.
+func (r *MagicLinkRepository) StartCooldown(ctx context.Context, userID int, cooldown time.Duration) (bool, error) {
+	ok, err := r.Redis.SetNX(ctx, magicLinkCooldownKey(userID), 1, cooldown).Result()
+	if err != nil {
+		logger.Log.Error("Error checking magic link cooldown", "error", err)
+		return false, err
+	}
+	return ok, nil
+}
+
+func magicLinkKey(nonce string) string {
+	return "magic_link:" + nonce
+}
+
+func magicLinkCooldownKey(userID int) string {
+	return "magic_link:cooldown:" + strconv.Itoa(userID)
+}
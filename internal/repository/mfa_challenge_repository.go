@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"news-api/internal/models"
+	"news-api/pkg/logger"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// MFAChallengeRepository keeps pending MFA logins in Redis and remembers
+// which TOTP time steps were already used.
+type MFAChallengeRepository struct {
+	Redis *redis.Client
+}
+
+func NewMFAChallengeRepository(client *redis.Client) *MFAChallengeRepository {
+	return &MFAChallengeRepository{Redis: client}
+}
+
+func (r *MFAChallengeRepository) Create(ctx context.Context, tokenHash string, challenge models.MFAChallenge, ttl time.Duration) error {
+	key := mfaChallengeKey(tokenHash)
+	pipe := r.Redis.TxPipeline()
+	pipe.HSet(ctx, key, "user_id", challenge.UserID, "purpose", challenge.Purpose, "attempts", 0)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Log.Error("Error creating MFA challenge", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *MFAChallengeRepository) Get(ctx context.Context, tokenHash string) (*models.MFAChallenge, error) {
+	values, err := r.Redis.HGetAll(ctx, mfaChallengeKey(tokenHash)).Result()
+	if err != nil {
+		logger.Log.Error("Error fetching MFA challenge", "error", err)
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	userID, _ := strconv.Atoi(values["user_id"])
+	attempts, _ := strconv.ParseInt(values["attempts"], 10, 64)
+	return &models.MFAChallenge{UserID: userID, Purpose: values["purpose"], Attempts: attempts}, nil
+}
+
+// AddAttempt counts a wrong code for the challenge and returns the total.
+func (r *MFAChallengeRepository) AddAttempt(ctx context.Context, tokenHash string) (int64, error) {
+	n, err := r.Redis.HIncrBy(ctx, mfaChallengeKey(tokenHash), "attempts", 1).Result()
+	if err != nil {
+		logger.Log.Error("Error counting MFA attempt", "error", err)
+		return 0, err
+	}
+	return n, nil
+}
+
+func (r *MFAChallengeRepository) Delete(ctx context.Context, tokenHash string) error {
+	return r.Redis.Del(ctx, mfaChallengeKey(tokenHash)).Err()
+}
+
+// ClaimStep records that the user spent the code of a TOTP time step. It
+// returns false if the step was already used.
+func (r *MFAChallengeRepository) ClaimStep(ctx context.Context, userID int, step int64, ttl time.Duration) (bool, error) {
+	key := "mfa_step:" + strconv.Itoa(userID) + ":" + strconv.FormatInt(step, 10)
+	ok, err := r.Redis.SetNX(ctx, key, 1, ttl).Result()
+	if err != nil {
+		logger.Log.Error("Error claiming TOTP step", "error", err)
+		return false, err
+	}
+	return ok, nil
+}
+
+func mfaChallengeKey(tokenHash string) string {
+	return "mfa_challenge:" + tokenHash
+}
@@ -1,12 +1,17 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	errors2 "news-api/internal/dto/errors"
 	"news-api/internal/models"
 	"news-api/pkg/logger"
 	"strings"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 type NewsRepository struct {
@@ -17,65 +22,370 @@ func NewNewsRepository(db *sql.DB) *NewsRepository {
 	return &NewsRepository{DB: db}
 }
 
+const newsColumns = `id, title, description, author_id, status, published_at, publish_at, unpublish_at, created_at, updated_at, deleted_at, deleted_by`
+
+// newsScheduleLockKey names the advisory lock held while scheduled changes
+// are applied, so that replicas do not apply them at the same time.
+const newsScheduleLockKey = 0x6e657773
+
+func scanNews(row rowScanner, n *models.News) error {
+	return row.Scan(
+		&n.ID, &n.Title, &n.Description, &n.AuthorID, &n.Status,
+		&n.PublishedAt, &n.PublishAt, &n.UnpublishAt, &n.CreatedAt, &n.UpdatedAt, &n.DeletedAt, &n.DeletedBy,
+	)
+}
+
+// Create inserts the news together with its first revision.
 func (r *NewsRepository) Create(news *models.News) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		logger.Log.Error("Error starting transaction", "error", err)
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO news (title, description, author_id)
-		VALUES ($1, $2, $3)
+		INSERT INTO news (title, description, author_id, status)
+		VALUES ($1, $2, $3, $4)
 		RETURNING id, created_at, updated_at
 	`
-	err := r.DB.QueryRow(query, news.Title, news.Description, news.AuthorID).
+	err = tx.QueryRow(query, news.Title, news.Description, news.AuthorID, news.Status).
 		Scan(&news.ID, &news.CreatedAt, &news.UpdatedAt)
 	if err != nil {
-		logger.Log.Error("Error creating news", err)
+		logger.Log.Error("Error creating news", "error", err)
+		return err
+	}
+	_, err = tx.Exec(`
+		INSERT INTO news_revisions (news_id, revision, title, description, editor_id, created_at)
+		VALUES ($1, 1, $2, $3, $4, $5)
+	`, news.ID, news.Title, news.Description, news.AuthorID, news.CreatedAt)
+	if err != nil {
+		logger.Log.Error("Error creating first news revision", "error", err)
+		return err
+	}
+	if err := setTaxonomy(tx, news); err != nil {
+		logger.Log.Error("Error saving news taxonomy", "error", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Log.Error("Error committing news", "error", err)
 		return err
 	}
 	return nil
 }
 
-func (r *NewsRepository) Update(news *models.News) error {
+// Update saves the new text of the news and records it as the next revision,
+// filling in rev. Categories and tags are replaced unless they are nil. The
+// row lock taken by the update keeps concurrent editors from getting the same
+// revision number. News that is no longer live gives ErrNotFound.
+func (r *NewsRepository) Update(news *models.News, rev *models.NewsRevision) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		logger.Log.Error("Error starting transaction", "error", err)
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		UPDATE news
 		SET title=$1, description=$2, updated_at=NOW()
-		WHERE id=$3
+		WHERE id=$3 AND deleted_at IS NULL
 		RETURNING updated_at
 	`
-	err := r.DB.QueryRow(query, news.Title, news.Description, news.ID).Scan(&news.UpdatedAt)
+	err = tx.QueryRow(query, news.Title, news.Description, news.ID).Scan(&news.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Deleted or moved to the trash since it was read.
+		return errors2.ErrNotFound
+	}
 	if err != nil {
-		logger.Log.Error("Error updating news", err)
+		logger.Log.Error("Error updating news", "error", err)
+		return err
+	}
+
+	rev.NewsID = news.ID
+	rev.Title = news.Title
+	rev.Description = news.Description
+	err = tx.QueryRow(`
+		INSERT INTO news_revisions (news_id, revision, title, description, editor_id, restored_from, created_at)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5, $6
+		FROM news_revisions WHERE news_id=$1
+		RETURNING id, revision, created_at
+	`, rev.NewsID, rev.Title, rev.Description, rev.EditorID, rev.RestoredFrom, news.UpdatedAt).
+		Scan(&rev.ID, &rev.Revision, &rev.CreatedAt)
+	if err != nil {
+		logger.Log.Error("Error creating news revision", "error", err)
+		return err
+	}
+	if err := setTaxonomy(tx, news); err != nil {
+		logger.Log.Error("Error saving news taxonomy", "error", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Log.Error("Error committing news update", "error", err)
 		return err
 	}
 	return nil
 }
 
-func (r *NewsRepository) Delete(id int) error {
-	query := `DELETE FROM news WHERE id=$1`
-	_, err := r.DB.Exec(query, id)
+// setTaxonomy replaces the categories and tags of the news with those it
+// carries, creating tags that do not exist yet. A nil list is left as is.
+func setTaxonomy(tx *sql.Tx, news *models.News) error {
+	if news.CategoryIDs != nil {
+		if _, err := tx.Exec(`DELETE FROM news_categories WHERE news_id=$1`, news.ID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`
+			INSERT INTO news_categories (news_id, category_id)
+			SELECT $1, unnest($2::int[])
+		`, news.ID, pq.Array(news.CategoryIDs))
+		if err != nil {
+			return err
+		}
+	}
+	if news.Tags != nil {
+		if _, err := tx.Exec(`DELETE FROM news_tags WHERE news_id=$1`, news.ID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`INSERT INTO tags (name) SELECT unnest($1::text[]) ON CONFLICT (name) DO NOTHING`, pq.Array(news.Tags))
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`
+			INSERT INTO news_tags (news_id, tag_id)
+			SELECT $1, id FROM tags WHERE name = ANY($2)
+		`, news.ID, pq.Array(news.Tags))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadTaxonomy fills in the categories and tags of the news.
+func (r *NewsRepository) loadTaxonomy(newsList []models.News) error {
+	if len(newsList) == 0 {
+		return nil
+	}
+	ids := make([]int, len(newsList))
+	byID := make(map[int]*models.News, len(newsList))
+	for i := range newsList {
+		n := &newsList[i]
+		n.CategoryIDs, n.Tags = []int{}, []string{}
+		ids[i] = n.ID
+		byID[n.ID] = n
+	}
+
+	rows, err := r.DB.Query(`
+		SELECT news_id, category_id FROM news_categories
+		WHERE news_id = ANY($1) ORDER BY category_id
+	`, pq.Array(ids))
 	if err != nil {
-		logger.Log.Error("Error deleting news", err)
+		logger.Log.Error("Error loading news categories", "error", err)
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var newsID, categoryID int
+		if err := rows.Scan(&newsID, &categoryID); err != nil {
+			logger.Log.Error("Error scanning news category", "error", err)
+			return err
+		}
+		byID[newsID].CategoryIDs = append(byID[newsID].CategoryIDs, categoryID)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	tagRows, err := r.DB.Query(`
+		SELECT nt.news_id, t.name FROM news_tags nt
+		JOIN tags t ON t.id = nt.tag_id
+		WHERE nt.news_id = ANY($1) ORDER BY t.name
+	`, pq.Array(ids))
+	if err != nil {
+		logger.Log.Error("Error loading news tags", "error", err)
+		return err
+	}
+	defer tagRows.Close()
+	for tagRows.Next() {
+		var newsID int
+		var tag string
+		if err := tagRows.Scan(&newsID, &tag); err != nil {
+			logger.Log.Error("Error scanning news tag", "error", err)
+			return err
+		}
+		byID[newsID].Tags = append(byID[newsID].Tags, tag)
+	}
+	return tagRows.Err()
+}
+
+func (r *NewsRepository) withTaxonomy(news *models.News) (*models.News, error) {
+	list := []models.News{*news}
+	if err := r.loadTaxonomy(list); err != nil {
+		return nil, err
+	}
+	return &list[0], nil
+}
+
+// UpdateStatus moves the news from one status to another, stores its
+// schedule and stamps published_at when it is published. It reports false,
+// changing nothing, if the news is no longer in status from.
+func (r *NewsRepository) UpdateStatus(news *models.News, from string) (bool, error) {
+	query := `
+		UPDATE news
+		SET status=$1,
+		    published_at=CASE WHEN $1 = 'published' THEN NOW() ELSE published_at END,
+		    publish_at=$4, unpublish_at=$5,
+		    updated_at=NOW()
+		WHERE id=$2 AND status=$3 AND deleted_at IS NULL
+		RETURNING published_at, updated_at
+	`
+	err := r.DB.QueryRow(query, news.Status, news.ID, from, news.PublishAt, news.UnpublishAt).
+		Scan(&news.PublishedAt, &news.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		logger.Log.Error("Error updating news status", "error", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// ApplySchedule publishes scheduled news whose publish_at has come and
+// archives published news whose unpublish_at has passed, and returns the ids
+// of both. While another replica is doing the same it returns nothing.
+func (r *NewsRepository) ApplySchedule(ctx context.Context) (published, archived []int, err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Log.Error("Error starting transaction", "error", err)
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, newsScheduleLockKey).Scan(&locked); err != nil {
+		logger.Log.Error("Error taking news schedule lock", "error", err)
+		return nil, nil, err
+	}
+	if !locked {
+		return nil, nil, nil
+	}
+
+	published, err = updatedIDs(ctx, tx, `
+		UPDATE news SET status='published', published_at=publish_at, updated_at=NOW()
+		WHERE status='scheduled' AND publish_at <= NOW() AND deleted_at IS NULL
+		RETURNING id
+	`)
+	if err != nil {
+		logger.Log.Error("Error publishing scheduled news", "error", err)
+		return nil, nil, err
+	}
+	archived, err = updatedIDs(ctx, tx, `
+		UPDATE news SET status='archived', updated_at=NOW()
+		WHERE status='published' AND unpublish_at <= NOW() AND deleted_at IS NULL
+		RETURNING id
+	`)
+	if err != nil {
+		logger.Log.Error("Error archiving expired news", "error", err)
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Log.Error("Error committing news schedule", "error", err)
+		return nil, nil, err
+	}
+	return published, archived, nil
+}
+
+func updatedIDs(ctx context.Context, tx *sql.Tx, query string) ([]int, error) {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Delete moves the news to the trash. Trashed news is left out of every read
+// except ListDeleted until it is restored or purged.
+func (r *NewsRepository) Delete(id, deletedBy int) error {
+	query := `UPDATE news SET deleted_at=NOW(), deleted_by=$2 WHERE id=$1 AND deleted_at IS NULL`
+	_, err := r.DB.Exec(query, id, deletedBy)
+	if err != nil {
+		logger.Log.Error("Error deleting news", "error", err)
 		return err
 	}
 	return nil
 }
 
+// Restore takes the news out of the trash. It returns nil if the news is not
+// in the trash.
+func (r *NewsRepository) Restore(id int) (*models.News, error) {
+	news := &models.News{}
+	query := `
+		UPDATE news SET deleted_at=NULL, deleted_by=NULL
+		WHERE id=$1 AND deleted_at IS NOT NULL
+		RETURNING ` + newsColumns
+	err := scanNews(r.DB.QueryRow(query, id), news)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		logger.Log.Error("Error restoring news", "error", err)
+		return nil, err
+	}
+	return r.withTaxonomy(news)
+}
+
+// PurgeDeleted removes for good the news that went to the trash before the
+// given time, with its revisions, and returns the ids of what it removed.
+func (r *NewsRepository) PurgeDeleted(ctx context.Context, before time.Time) ([]int, error) {
+	rows, err := r.DB.QueryContext(ctx, `DELETE FROM news WHERE deleted_at < $1 RETURNING id`, before)
+	if err != nil {
+		logger.Log.Error("Error purging deleted news", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			logger.Log.Error("Error scanning purged news id", "error", err)
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 func (r *NewsRepository) GetByID(id int) (*models.News, error) {
 	news := &models.News{}
-	query := `SELECT id, title, description, author_id, created_at, updated_at FROM news WHERE id=$1`
-	err := r.DB.QueryRow(query, id).Scan(
-		&news.ID, &news.Title, &news.Description,
-		&news.AuthorID, &news.CreatedAt, &news.UpdatedAt,
-	)
+	query := `SELECT ` + newsColumns + ` FROM news WHERE id=$1 AND deleted_at IS NULL`
+	err := scanNews(r.DB.QueryRow(query, id), news)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
-		logger.Log.Error("Error fetching news by id", err)
+		logger.Log.Error("Error fetching news by id", "error", err)
 		return nil, err
 	}
-	return news, nil
+	return r.withTaxonomy(news)
 }
 
 func (r *NewsRepository) List(params models.NewsListParams) ([]models.News, error) {
-	query := `SELECT id, title, description, author_id, created_at, updated_at FROM news WHERE 1=1`
+	query := `SELECT ` + newsColumns + ` FROM news WHERE deleted_at IS NULL`
 	args := []interface{}{}
 	argPos := 1
 
@@ -91,12 +401,47 @@ func (r *NewsRepository) List(params models.NewsListParams) ([]models.News, erro
 		argPos++
 	}
 
-	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	if params.Category != nil {
+		query += fmt.Sprintf(` AND id IN (
+			SELECT nc.news_id FROM news_categories nc
+			WHERE nc.category_id IN (
+				WITH RECURSIVE sub AS (
+					SELECT id FROM categories WHERE slug=$%d
+					UNION ALL
+					SELECT c.id FROM categories c JOIN sub ON c.parent_id = sub.id
+				)
+				SELECT id FROM sub
+			))`, argPos)
+		args = append(args, *params.Category)
+		argPos++
+	}
+	if params.Tag != nil {
+		query += fmt.Sprintf(` AND id IN (
+			SELECT nt.news_id FROM news_tags nt JOIN tags t ON t.id = nt.tag_id WHERE t.name=$%d
+		)`, argPos)
+		args = append(args, *params.Tag)
+		argPos++
+	}
+
+	order := "created_at"
+	if params.Status != nil {
+		query += fmt.Sprintf(" AND status=$%d", argPos)
+		args = append(args, *params.Status)
+		argPos++
+		if *params.Status == models.NewsPublished {
+			// The scheduler may run a little late; embargoes and expiry hold
+			// regardless.
+			query += " AND (publish_at IS NULL OR publish_at <= NOW()) AND (unpublish_at IS NULL OR unpublish_at > NOW())"
+			order = "published_at"
+		}
+	}
+
+	query += fmt.Sprintf(" ORDER BY %s DESC LIMIT $%d OFFSET $%d", order, argPos, argPos+1)
 	args = append(args, params.Limit, params.Offset)
 
 	rows, err := r.DB.Query(query, args...)
 	if err != nil {
-		logger.Log.Error("Error listing news", err)
+		logger.Log.Error("Error listing news", "error", err)
 		return nil, err
 	}
 	defer rows.Close()
@@ -104,11 +449,140 @@ func (r *NewsRepository) List(params models.NewsListParams) ([]models.News, erro
 	newsList := []models.News{}
 	for rows.Next() {
 		var n models.News
-		if err := rows.Scan(&n.ID, &n.Title, &n.Description, &n.AuthorID, &n.CreatedAt, &n.UpdatedAt); err != nil {
-			logger.Log.Error("Error scanning news row", err)
+		if err := scanNews(rows, &n); err != nil {
+			logger.Log.Error("Error scanning news row", "error", err)
 			continue
 		}
 		newsList = append(newsList, n)
 	}
+	if err := r.loadTaxonomy(newsList); err != nil {
+		return nil, err
+	}
+	return newsList, nil
+}
+
+// ListDeleted returns a page of the trash, most recently deleted first, and
+// the number of trashed news matching the filters.
+func (r *NewsRepository) ListDeleted(params models.NewsListParams) ([]models.News, int, error) {
+	where := ` WHERE deleted_at IS NOT NULL`
+	args := []interface{}{}
+	argPos := 1
+
+	if params.AuthorID != nil {
+		where += fmt.Sprintf(" AND author_id=$%d", argPos)
+		args = append(args, *params.AuthorID)
+		argPos++
+	}
+	if params.Search != nil && strings.TrimSpace(*params.Search) != "" {
+		where += fmt.Sprintf(" AND title ILIKE $%d", argPos)
+		args = append(args, "%"+*params.Search+"%")
+		argPos++
+	}
+
+	var total int
+	if err := r.DB.QueryRow(`SELECT COUNT(*) FROM news`+where, args...).Scan(&total); err != nil {
+		logger.Log.Error("Error counting deleted news", "error", err)
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + newsColumns + ` FROM news` + where +
+		fmt.Sprintf(" ORDER BY deleted_at DESC, id DESC LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	args = append(args, params.Limit, params.Offset)
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		logger.Log.Error("Error listing deleted news", "error", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	newsList := []models.News{}
+	for rows.Next() {
+		var n models.News
+		if err := scanNews(rows, &n); err != nil {
+			logger.Log.Error("Error scanning news row", "error", err)
+			return nil, 0, err
+		}
+		newsList = append(newsList, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if err := r.loadTaxonomy(newsList); err != nil {
+		return nil, 0, err
+	}
+	return newsList, total, nil
+}
+
+// ListByAuthor returns every news item of the author, newest first.
+func (r *NewsRepository) ListByAuthor(authorID int) ([]models.News, error) {
+	query := `SELECT ` + newsColumns + ` FROM news WHERE author_id=$1 AND deleted_at IS NULL ORDER BY created_at DESC`
+	rows, err := r.DB.Query(query, authorID)
+	if err != nil {
+		logger.Log.Error("Error listing news by author", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	newsList := []models.News{}
+	for rows.Next() {
+		var n models.News
+		if err := scanNews(rows, &n); err != nil {
+			logger.Log.Error("Error scanning news row", "error", err)
+			return nil, err
+		}
+		newsList = append(newsList, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.loadTaxonomy(newsList); err != nil {
+		return nil, err
+	}
 	return newsList, nil
 }
+
+const revisionColumns = `id, news_id, revision, title, description, editor_id, restored_from, created_at`
+
+func scanRevision(row rowScanner, rev *models.NewsRevision) error {
+	return row.Scan(
+		&rev.ID, &rev.NewsID, &rev.Revision, &rev.Title, &rev.Description,
+		&rev.EditorID, &rev.RestoredFrom, &rev.CreatedAt,
+	)
+}
+
+// ListRevisions returns every revision of the news, newest first.
+func (r *NewsRepository) ListRevisions(newsID int) ([]models.NewsRevision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM news_revisions WHERE news_id=$1 ORDER BY revision DESC`
+	rows, err := r.DB.Query(query, newsID)
+	if err != nil {
+		logger.Log.Error("Error listing news revisions", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []models.NewsRevision{}
+	for rows.Next() {
+		var rev models.NewsRevision
+		if err := scanRevision(rows, &rev); err != nil {
+			logger.Log.Error("Error scanning news revision row", "error", err)
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+func (r *NewsRepository) GetRevision(newsID, revision int) (*models.NewsRevision, error) {
+	rev := &models.NewsRevision{}
+	query := `SELECT ` + revisionColumns + ` FROM news_revisions WHERE news_id=$1 AND revision=$2`
+	err := scanRevision(r.DB.QueryRow(query, newsID, revision), rev)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		logger.Log.Error("Error fetching news revision", "error", err)
+		return nil, err
+	}
+	return rev, nil
+}
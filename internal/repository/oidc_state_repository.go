@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"news-api/internal/models"
+	"news-api/pkg/logger"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// OIDCStateRepository keeps the PKCE verifier and nonce of a pending SSO
+// login in Redis until the identity provider redirects back.
+type OIDCStateRepository struct {
+	Redis *redis.Client
+}
+
+func NewOIDCStateRepository(client *redis.Client) *OIDCStateRepository {
+	return &OIDCStateRepository{Redis: client}
+}
+
+func (r *OIDCStateRepository) Save(ctx context.Context, stateHash string, state models.OIDCState, ttl time.Duration) error {
+	key := oidcStateKey(stateHash)
+	pipe := r.Redis.TxPipeline()
+	pipe.HSet(ctx, key, "code_verifier", state.CodeVerifier, "nonce", state.Nonce)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Log.Error("Error saving OIDC state", "error", err)
+		return err
+	}
+	return nil
+}
+
+// Consume returns and deletes the state, so that a callback can be used only
+// once. It returns nil if the state is unknown or expired.
+func (r *OIDCStateRepository) Consume(ctx context.Context, stateHash string) (*models.OIDCState, error) {
+	key := oidcStateKey(stateHash)
+	pipe := r.Redis.TxPipeline()
+	get := pipe.HGetAll(ctx, key)
+	pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Log.Error("Error consuming OIDC state", "error", err)
+		return nil, err
+	}
+
+	values := get.Val()
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return &models.OIDCState{CodeVerifier: values["code_verifier"], Nonce: values["nonce"]}, nil
+}
+
+func oidcStateKey(stateHash string) string {
+	return "oidc_state:" + stateHash
+}
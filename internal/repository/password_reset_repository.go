@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"news-api/internal/models"
+	"news-api/pkg/logger"
+	"time"
+)
+
+type PasswordResetRepository struct {
+	DB *sql.DB
+}
+
+func NewPasswordResetRepository(db *sql.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{DB: db}
+}
+
+// Create stores the token unless another one was created for the user less
+// than cooldown ago, and reports whether it did.
+func (r *PasswordResetRepository) Create(ctx context.Context, t *models.PasswordResetToken, cooldown time.Duration) (bool, error) {
+	query := `
+		INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+		SELECT $1, $2, $3
+		WHERE NOT EXISTS (
+			SELECT 1 FROM password_reset_tokens
+			WHERE user_id=$1 AND created_at > NOW() - make_interval(secs => $4)
+		)
+		RETURNING id, created_at
+	`
+	err := r.DB.QueryRowContext(ctx, query, t.UserID, t.TokenHash, t.ExpiresAt, cooldown.Seconds()).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		logger.Log.Error("Error creating password reset token", "error", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// Get returns the token if it is unused and unexpired, or nil, without
+// using it up.
+func (r *PasswordResetRepository) Get(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	t := &models.PasswordResetToken{}
+	query := `
+		SELECT id, user_id, token_hash, expires_at, used_at, created_at
+		FROM password_reset_tokens
+		WHERE token_hash=$1 AND used_at IS NULL AND expires_at > NOW()
+	`
+	err := r.DB.QueryRowContext(ctx, query, tokenHash).Scan(
+		&t.ID, &t.UserID, &t.TokenHash, &t.ExpiresAt, &t.UsedAt, &t.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		logger.Log.Error("Error fetching password reset token", "error", err)
+		return nil, err
+	}
+	return t, nil
+}
+
+// Consume marks an unused, unexpired token as used and returns it. It returns
+// nil if no such token exists, which makes every token single-use even under
+// concurrent requests.
+func (r *PasswordResetRepository) Consume(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	t := &models.PasswordResetToken{}
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at=NOW()
+		WHERE token_hash=$1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING id, user_id, token_hash, expires_at, used_at, created_at
+	`
+	err := r.DB.QueryRowContext(ctx, query, tokenHash).Scan(
+		&t.ID, &t.UserID, &t.TokenHash, &t.ExpiresAt, &t.UsedAt, &t.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		logger.Log.Error("Error consuming password reset token", "error", err)
+		return nil, err
+	}
+	return t, nil
+}
+
+// InvalidateByUser marks every outstanding token of the user as used.
+func (r *PasswordResetRepository) InvalidateByUser(ctx context.Context, userID int) error {
+	query := `UPDATE password_reset_tokens SET used_at=NOW() WHERE user_id=$1 AND used_at IS NULL`
+	if _, err := r.DB.ExecContext(ctx, query, userID); err != nil {
+		logger.Log.Error("Error invalidating password reset tokens", "error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"news-api/pkg/logger"
+)
+
+type RecoveryCodeRepository struct {
+	DB *sql.DB
+}
+
+func NewRecoveryCodeRepository(db *sql.DB) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{DB: db}
+}
+
+// Replace drops every recovery code of the user and stores the new hashes.
+func (r *RecoveryCodeRepository) Replace(ctx context.Context, userID int, codeHashes []string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Log.Error("Error starting transaction", "error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id=$1`, userID); err != nil {
+		logger.Log.Error("Error deleting recovery codes", "error", err)
+		return err
+	}
+	for _, hash := range codeHashes {
+		_, err := tx.ExecContext(ctx, `INSERT INTO mfa_recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, hash)
+		if err != nil {
+			logger.Log.Error("Error creating recovery code", "error", err)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Consume marks an unused recovery code as used. It returns false if the user
+// has no such unused code.
+func (r *RecoveryCodeRepository) Consume(ctx context.Context, userID int, codeHash string) (bool, error) {
+	query := `UPDATE mfa_recovery_codes SET used_at=NOW() WHERE user_id=$1 AND code_hash=$2 AND used_at IS NULL`
+	res, err := r.DB.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		logger.Log.Error("Error consuming recovery code", "error", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *RecoveryCodeRepository) DeleteByUser(ctx context.Context, userID int) error {
+	if _, err := r.DB.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id=$1`, userID); err != nil {
+		logger.Log.Error("Error deleting recovery codes", "error", err)
+		return err
+	}
+	return nil
+}
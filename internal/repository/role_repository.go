@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"news-api/internal/models"
+	"news-api/pkg/logger"
+
+	"github.com/lib/pq"
+)
+
+const roleSelect = `
+	SELECT r.name, r.description, r.is_system, r.created_at,
+	       COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')
+	FROM roles r
+	LEFT JOIN role_permissions rp ON rp.role = r.name
+`
+
+type RoleRepository struct {
+	DB *sql.DB
+}
+
+func NewRoleRepository(db *sql.DB) *RoleRepository {
+	return &RoleRepository{DB: db}
+}
+
+func (r *RoleRepository) List(ctx context.Context) ([]models.Role, error) {
+	rows, err := r.DB.QueryContext(ctx, roleSelect+` GROUP BY r.name ORDER BY r.name`)
+	if err != nil {
+		logger.Log.Error("Error listing roles", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []models.Role
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			logger.Log.Error("Error scanning role", "error", err)
+			return nil, err
+		}
+		roles = append(roles, *role)
+	}
+	return roles, rows.Err()
+}
+
+// Get returns the role with its permissions, or nil if it does not exist.
+func (r *RoleRepository) Get(ctx context.Context, name string) (*models.Role, error) {
+	role, err := scanRole(r.DB.QueryRowContext(ctx, roleSelect+` WHERE r.name=$1 GROUP BY r.name`, name))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		logger.Log.Error("Error fetching role", "error", err)
+		return nil, err
+	}
+	return role, nil
+}
+
+func (r *RoleRepository) Create(ctx context.Context, role *models.Role) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		query := `INSERT INTO roles (name, description) VALUES ($1, $2) RETURNING created_at`
+		if err := tx.QueryRowContext(ctx, query, role.Name, role.Description).Scan(&role.CreatedAt); err != nil {
+			return err
+		}
+		return insertRolePermissions(ctx, tx, role.Name, role.Permissions)
+	})
+}
+
+// Update replaces the description and the permissions of the role.
+func (r *RoleRepository) Update(ctx context.Context, role *models.Role) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `UPDATE roles SET description=$1 WHERE name=$2`, role.Description, role.Name); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM role_permissions WHERE role=$1`, role.Name); err != nil {
+			return err
+		}
+		return insertRolePermissions(ctx, tx, role.Name, role.Permissions)
+	})
+}
+
+func (r *RoleRepository) Delete(ctx context.Context, name string) error {
+	if _, err := r.DB.ExecContext(ctx, `DELETE FROM roles WHERE name=$1 AND NOT is_system`, name); err != nil {
+		logger.Log.Error("Error deleting role", "error", err)
+		return err
+	}
+	return nil
+}
+
+// CountUsers counts the users that have the role.
+func (r *RoleRepository) CountUsers(ctx context.Context, name string) (int, error) {
+	var n int
+	if err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE role=$1`, name).Scan(&n); err != nil {
+		logger.Log.Error("Error counting role users", "error", err)
+		return 0, err
+	}
+	return n, nil
+}
+
+func (r *RoleRepository) ListPermissions(ctx context.Context) ([]models.Permission, error) {
+	rows, err := r.DB.QueryContext(ctx, `SELECT name, description FROM permissions ORDER BY name`)
+	if err != nil {
+		logger.Log.Error("Error listing permissions", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var perms []models.Permission
+	for rows.Next() {
+		var p models.Permission
+		if err := rows.Scan(&p.Name, &p.Description); err != nil {
+			logger.Log.Error("Error scanning permission", "error", err)
+			return nil, err
+		}
+		perms = append(perms, p)
+	}
+	return perms, rows.Err()
+}
+
+func (r *RoleRepository) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Log.Error("Error starting transaction", "error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		logger.Log.Error("Error saving role", "error", err)
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertRolePermissions(ctx context.Context, tx *sql.Tx, role string, permissions []string) error {
+	query := `INSERT INTO role_permissions (role, permission) SELECT $1, unnest($2::text[])`
+	_, err := tx.ExecContext(ctx, query, role, pq.Array(permissions))
+	return err
+}
+
+func scanRole(row rowScanner) (*models.Role, error) {
+	role := &models.Role{}
+	err := row.Scan(&role.Name, &role.Description, &role.System, &role.CreatedAt, pq.Array(&role.Permissions))
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
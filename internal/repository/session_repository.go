@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"news-api/internal/models"
+	"news-api/pkg/logger"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// rotateSessionScript swaps the refresh token of a session only if it still
+// holds the presented one, so two concurrent refreshes cannot both succeed.
+var rotateSessionScript = redis.NewScript(`
+if redis.call("HGET", KEYS[1], "refresh") ~= ARGV[1] then
+	return 0
+end
+redis.call("HSET", KEYS[1], "refresh", ARGV[2], "ip", ARGV[3], "user_agent", ARGV[4], "last_used_at", ARGV[5])
+redis.call("PEXPIRE", KEYS[1], ARGV[6])
+return 1
+`)
+
+type SessionRepository struct {
+	Redis *redis.Client
+}
+
+func NewSessionRepository(client *redis.Client) *SessionRepository {
+	return &SessionRepository{Redis: client}
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session *models.Session, ttl time.Duration) error {
+	key := sessionKey(session.ID)
+	userKey := userSessionsKey(session.UserID)
+
+	pipe := r.Redis.TxPipeline()
+	pipe.HSet(ctx, key, map[string]interface{}{
+		"user_id":      session.UserID,
+		"refresh":      session.RefreshToken,
+		"ip":           session.IP,
+		"user_agent":   session.UserAgent,
+		"created_at":   session.CreatedAt.Unix(),
+		"last_used_at": session.LastUsedAt.Unix(),
+	})
+	pipe.Expire(ctx, key, ttl)
+	pipe.SAdd(ctx, userKey, session.ID)
+	pipe.Expire(ctx, userKey, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Log.Error("Error creating session", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *SessionRepository) GetByID(ctx context.Context, id string) (*models.Session, error) {
+	values, err := r.Redis.HGetAll(ctx, sessionKey(id)).Result()
+	if err != nil {
+		logger.Log.Error("Error fetching session", "error", err)
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return parseSession(id, values), nil
+}
+
+// Rotate replaces the refresh token of a session and records where it was used
+// from. It returns false when the stored token no longer matches oldRefresh.
+func (r *SessionRepository) Rotate(ctx context.Context, session *models.Session, oldRefresh string, ttl time.Duration) (bool, error) {
+	rotated, err := rotateSessionScript.Run(ctx, r.Redis, []string{sessionKey(session.ID)},
+		oldRefresh, session.RefreshToken, session.IP, session.UserAgent,
+		session.LastUsedAt.Unix(), ttl.Milliseconds()).Int()
+	if err != nil {
+		logger.Log.Error("Error rotating session", "error", err)
+		return false, err
+	}
+	if rotated == 0 {
+		return false, nil
+	}
+
+	if err := r.Redis.Expire(ctx, userSessionsKey(session.UserID), ttl).Err(); err != nil {
+		logger.Log.Error("Error extending user sessions", "error", err)
+		return true, err
+	}
+	return true, nil
+}
+
+func (r *SessionRepository) ListByUser(ctx context.Context, userID int) ([]models.Session, error) {
+	userKey := userSessionsKey(userID)
+	ids, err := r.Redis.SMembers(ctx, userKey).Result()
+	if err != nil {
+		logger.Log.Error("Error listing sessions", "error", err)
+		return nil, err
+	}
+
+	sessions := []models.Session{}
+	for _, id := range ids {
+		s, err := r.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			// The session hash expired on its own; drop the dangling reference.
+			r.Redis.SRem(ctx, userKey, id)
+			continue
+		}
+		sessions = append(sessions, *s)
+	}
+	return sessions, nil
+}
+
+func (r *SessionRepository) Delete(ctx context.Context, userID int, id string) error {
+	pipe := r.Redis.TxPipeline()
+	pipe.Del(ctx, sessionKey(id))
+	pipe.SRem(ctx, userSessionsKey(userID), id)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Log.Error("Error deleting session", "error", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteByUser removes every session of the user except keepID, if set, and
+// returns the ids of the removed sessions.
+func (r *SessionRepository) DeleteByUser(ctx context.Context, userID int, keepID string) ([]string, error) {
+	userKey := userSessionsKey(userID)
+	ids, err := r.Redis.SMembers(ctx, userKey).Result()
+	if err != nil {
+		logger.Log.Error("Error listing sessions", "error", err)
+		return nil, err
+	}
+
+	removed := []string{}
+	pipe := r.Redis.TxPipeline()
+	for _, id := range ids {
+		if id == keepID {
+			continue
+		}
+		pipe.Del(ctx, sessionKey(id))
+		pipe.SRem(ctx, userKey, id)
+		removed = append(removed, id)
+	}
+	if len(removed) == 0 {
+		return removed, nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Log.Error("Error deleting sessions", "error", err)
+		return nil, err
+	}
+	return removed, nil
+}
+
+func parseSession(id string, values map[string]string) *models.Session {
+	userID, _ := strconv.Atoi(values["user_id"])
+	createdAt, _ := strconv.ParseInt(values["created_at"], 10, 64)
+	lastUsedAt, _ := strconv.ParseInt(values["last_used_at"], 10, 64)
+
+	return &models.Session{
+		ID:           id,
+		UserID:       userID,
+		RefreshToken: values["refresh"],
+		UserAgent:    values["user_agent"],
+		IP:           values["ip"],
+		CreatedAt:    time.Unix(createdAt, 0).UTC(),
+		LastUsedAt:   time.Unix(lastUsedAt, 0).UTC(),
+	}
+}
+
+func sessionKey(id string) string {
+	return "session:" + id
+}
+
+func userSessionsKey(userID int) string {
+	return "user_sessions:" + strconv.Itoa(userID)
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"news-api/pkg/logger"
+	"news-api/pkg/token"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	signingKeysKey     = "jwt:keys"
+	signingKeysLockKey = "jwt:keys:rotation_lock"
+)
+
+// sealLegacyKeyScript swaps a stored key for its encrypted form only if it
+// still holds the value that was read.
+var sealLegacyKeyScript = redis.NewScript(`
+if redis.call("HGET", KEYS[1], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[3])
+return 1
+`)
+
+// releaseRotationLockScript deletes the lock only if it still holds the value
+// set by the caller, so a holder that ran past the TTL cannot free a lock
+// taken over by another replica.
+var releaseRotationLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+return redis.call("DEL", KEYS[1])
+`)
+
+// storedSigningKey holds the private key sealed with AES-GCM, bound to its
+// kid. PrivateKey is the plain PEM kept by earlier versions; such keys are
+// sealed on their next load.
+type storedSigningKey struct {
+	ID          string    `json:"id"`
+	Algorithm   string    `json:"alg"`
+	PrivateKey  string    `json:"private_key,omitempty"`
+	SealedKey   []byte    `json:"sealed_key,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	ActivatesAt time.Time `json:"activates_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// SigningKeyRepository stores JWT signing keys in Redis so that all replicas
+// share them. Private keys are encrypted with the configured key. It
+// implements token.KeyStore.
+type SigningKeyRepository struct {
+	Redis *redis.Client
+	AEAD  cipher.AEAD
+}
+
+func NewSigningKeyRepository(client *redis.Client, aead cipher.AEAD) *SigningKeyRepository {
+	return &SigningKeyRepository{Redis: client, AEAD: aead}
+}
+
+func (r *SigningKeyRepository) LoadKeys(ctx context.Context) ([]*token.Key, error) {
+	values, err := r.Redis.HGetAll(ctx, signingKeysKey).Result()
+	if err != nil {
+		logger.Log.Error("Error loading signing keys", "error", err)
+		return nil, err
+	}
+
+	keys := make([]*token.Key, 0, len(values))
+	for kid, raw := range values {
+		var stored storedSigningKey
+		if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+			logger.Log.Error("Error decoding signing key", "error", err, "kid", kid)
+			return nil, errors.Join(token.ErrKeyUnreadable, err)
+		}
+		private, err := r.open(&stored)
+		if err != nil {
+			logger.Log.Error("Error decrypting signing key, check JWT_KEY_ENCRYPTION_KEY", "error", err, "kid", kid)
+			return nil, errors.Join(token.ErrKeyUnreadable, err)
+		}
+		key, err := token.ParseKey(stored.ID, stored.Algorithm, private, stored.CreatedAt, stored.ExpiresAt)
+		if err != nil {
+			logger.Log.Error("Error parsing signing key", "error", err, "kid", kid)
+			return nil, errors.Join(token.ErrKeyUnreadable, err)
+		}
+		key.ActivatesAt = stored.ActivatesAt
+
+		if stored.SealedKey == nil {
+			if err := r.sealLegacy(ctx, key, raw); err != nil {
+				return nil, err
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (r *SigningKeyRepository) open(stored *storedSigningKey) ([]byte, error) {
+	if stored.SealedKey == nil {
+		return []byte(stored.PrivateKey), nil
+	}
+	size := r.AEAD.NonceSize()
+	if len(stored.SealedKey) < size {
+		return nil, errors.New("sealed key too short")
+	}
+	nonce, sealed := stored.SealedKey[:size], stored.SealedKey[size:]
+	return r.AEAD.Open(nil, nonce, sealed, []byte(stored.ID))
+}
+
+func (r *SigningKeyRepository) seal(kid string, private []byte) ([]byte, error) {
+	nonce := make([]byte, r.AEAD.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return r.AEAD.Seal(nonce, nonce, private, []byte(kid)), nil
+}
+
+// sealLegacy replaces a key stored in plain PEM with its encrypted form,
+// unless another replica changed it since it was read.
+func (r *SigningKeyRepository) sealLegacy(ctx context.Context, key *token.Key, old string) error {
+	raw, err := r.marshal(key)
+	if err != nil {
+		return err
+	}
+	if err := sealLegacyKeyScript.Run(ctx, r.Redis, []string{signingKeysKey}, key.ID, old, raw).Err(); err != nil {
+		logger.Log.Error("Error encrypting signing key", "error", err, "kid", key.ID)
+		return err
+	}
+	logger.Log.Info("Signing key encrypted", "kid", key.ID)
+	return nil
+}
+
+func (r *SigningKeyRepository) marshal(key *token.Key) ([]byte, error) {
+	private, err := key.MarshalPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := r.seal(key.ID, private)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(storedSigningKey{
+		ID:          key.ID,
+		Algorithm:   key.Algorithm,
+		SealedKey:   sealed,
+		CreatedAt:   key.CreatedAt,
+		ActivatesAt: key.ActivatesAt,
+		ExpiresAt:   key.ExpiresAt,
+	})
+}
+
+func (r *SigningKeyRepository) SaveKey(ctx context.Context, key *token.Key) error {
+	raw, err := r.marshal(key)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Redis.HSet(ctx, signingKeysKey, key.ID, raw).Err(); err != nil {
+		logger.Log.Error("Error saving signing key", "error", err, "kid", key.ID)
+		return err
+	}
+	return nil
+}
+
+func (r *SigningKeyRepository) DeleteKey(ctx context.Context, kid string) error {
+	if err := r.Redis.HDel(ctx, signingKeysKey, kid).Err(); err != nil {
+		logger.Log.Error("Error deleting signing key", "error", err, "kid", kid)
+		return err
+	}
+	return nil
+}
+
+func (r *SigningKeyRepository) AcquireRotationLock(ctx context.Context, ttl time.Duration) (string, error) {
+	lock, _, err := token.NewSecret()
+	if err != nil {
+		return "", err
+	}
+	ok, err := r.Redis.SetNX(ctx, signingKeysLockKey, lock, ttl).Result()
+	if err != nil {
+		logger.Log.Error("Error acquiring key rotation lock", "error", err)
+		return "", err
+	}
+	if !ok {
+		return "", nil
+	}
+	return lock, nil
+}
+
+func (r *SigningKeyRepository) ReleaseRotationLock(ctx context.Context, lock string) error {
+	return releaseRotationLockScript.Run(ctx, r.Redis, []string{signingKeysLockKey}, lock).Err()
+}
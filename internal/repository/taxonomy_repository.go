@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"news-api/internal/models"
+	"news-api/pkg/logger"
+
+	"github.com/lib/pq"
+)
+
+// TaxonomyRepository stores categories and tags. Which news they are put on
+// is saved by NewsRepository.
+type TaxonomyRepository struct {
+	DB *sql.DB
+}
+
+func NewTaxonomyRepository(db *sql.DB) *TaxonomyRepository {
+	return &TaxonomyRepository{DB: db}
+}
+
+const categoryColumns = `id, parent_id, name, slug, description, created_at, updated_at`
+
+func scanCategory(row rowScanner, c *models.Category) error {
+	return row.Scan(&c.ID, &c.ParentID, &c.Name, &c.Slug, &c.Description, &c.CreatedAt, &c.UpdatedAt)
+}
+
+// ListCategories returns every category ordered by name; the tree is built
+// from ParentID.
+func (r *TaxonomyRepository) ListCategories(ctx context.Context) ([]models.Category, error) {
+	rows, err := r.DB.QueryContext(ctx, `SELECT `+categoryColumns+` FROM categories ORDER BY name, id`)
+	if err != nil {
+		logger.Log.Error("Error listing categories", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []models.Category{}
+	for rows.Next() {
+		var c models.Category
+		if err := scanCategory(rows, &c); err != nil {
+			logger.Log.Error("Error scanning category row", "error", err)
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+func (r *TaxonomyRepository) GetCategory(ctx context.Context, id int) (*models.Category, error) {
+	return r.getCategory(ctx, `SELECT `+categoryColumns+` FROM categories WHERE id=$1`, id)
+}
+
+func (r *TaxonomyRepository) GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	return r.getCategory(ctx, `SELECT `+categoryColumns+` FROM categories WHERE slug=$1`, slug)
+}
+
+func (r *TaxonomyRepository) getCategory(ctx context.Context, query string, arg interface{}) (*models.Category, error) {
+	c := &models.Category{}
+	if err := scanCategory(r.DB.QueryRowContext(ctx, query, arg), c); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		logger.Log.Error("Error fetching category", "error", err)
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *TaxonomyRepository) CreateCategory(ctx context.Context, c *models.Category) error {
+	query := `
+		INSERT INTO categories (parent_id, name, slug, description)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`
+	err := r.DB.QueryRowContext(ctx, query, c.ParentID, c.Name, c.Slug, c.Description).
+		Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		logger.Log.Error("Error creating category", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *TaxonomyRepository) UpdateCategory(ctx context.Context, c *models.Category) error {
+	query := `
+		UPDATE categories SET parent_id=$1, name=$2, slug=$3, description=$4, updated_at=NOW()
+		WHERE id=$5
+		RETURNING updated_at
+	`
+	err := r.DB.QueryRowContext(ctx, query, c.ParentID, c.Name, c.Slug, c.Description, c.ID).Scan(&c.UpdatedAt)
+	if err != nil {
+		logger.Log.Error("Error updating category", "error", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteCategory deletes the category and takes it off its news.
+func (r *TaxonomyRepository) DeleteCategory(ctx context.Context, id int) error {
+	if _, err := r.DB.ExecContext(ctx, `DELETE FROM categories WHERE id=$1`, id); err != nil {
+		logger.Log.Error("Error deleting category", "error", err)
+		return err
+	}
+	return nil
+}
+
+// CountCategories counts how many of the ids are existing categories.
+func (r *TaxonomyRepository) CountCategories(ctx context.Context, ids []int) (int, error) {
+	var n int
+	query := `SELECT COUNT(*) FROM categories WHERE id = ANY($1)`
+	if err := r.DB.QueryRowContext(ctx, query, pq.Array(ids)).Scan(&n); err != nil {
+		logger.Log.Error("Error counting categories", "error", err)
+		return 0, err
+	}
+	return n, nil
+}
+
+const tagSelect = `
+	SELECT t.id, t.name, t.created_at, COUNT(nt.news_id)
+	FROM tags t
+	LEFT JOIN news_tags nt ON nt.tag_id = t.id
+`
+
+func scanTag(row rowScanner, t *models.Tag) error {
+	return row.Scan(&t.ID, &t.Name, &t.CreatedAt, &t.NewsCount)
+}
+
+// ListTags returns every tag with the number of news carrying it, ordered by
+// name.
+func (r *TaxonomyRepository) ListTags(ctx context.Context) ([]models.Tag, error) {
+	rows, err := r.DB.QueryContext(ctx, tagSelect+` GROUP BY t.id ORDER BY t.name`)
+	if err != nil {
+		logger.Log.Error("Error listing tags", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var t models.Tag
+		if err := scanTag(rows, &t); err != nil {
+			logger.Log.Error("Error scanning tag row", "error", err)
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+func (r *TaxonomyRepository) GetTag(ctx context.Context, id int) (*models.Tag, error) {
+	return r.getTag(ctx, tagSelect+` WHERE t.id=$1 GROUP BY t.id`, id)
+}
+
+func (r *TaxonomyRepository) GetTagByName(ctx context.Context, name string) (*models.Tag, error) {
+	return r.getTag(ctx, tagSelect+` WHERE t.name=$1 GROUP BY t.id`, name)
+}
+
+func (r *TaxonomyRepository) getTag(ctx context.Context, query string, arg interface{}) (*models.Tag, error) {
+	t := &models.Tag{}
+	if err := scanTag(r.DB.QueryRowContext(ctx, query, arg), t); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		logger.Log.Error("Error fetching tag", "error", err)
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *TaxonomyRepository) CreateTag(ctx context.Context, t *models.Tag) error {
+	query := `INSERT INTO tags (name) VALUES ($1) RETURNING id, created_at`
+	if err := r.DB.QueryRowContext(ctx, query, t.Name).Scan(&t.ID, &t.CreatedAt); err != nil {
+		logger.Log.Error("Error creating tag", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *TaxonomyRepository) RenameTag(ctx context.Context, id int, name string) error {
+	if _, err := r.DB.ExecContext(ctx, `UPDATE tags SET name=$1 WHERE id=$2`, name, id); err != nil {
+		logger.Log.Error("Error renaming tag", "error", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteTag deletes the tag and takes it off its news.
+func (r *TaxonomyRepository) DeleteTag(ctx context.Context, id int) error {
+	if _, err := r.DB.ExecContext(ctx, `DELETE FROM tags WHERE id=$1`, id); err != nil {
+		logger.Log.Error("Error deleting tag", "error", err)
+		return err
+	}
+	return nil
+}
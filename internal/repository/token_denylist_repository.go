@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math"
+	"news-api/pkg/logger"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TokenDenylistRepository keeps revoked access tokens in Redis until they
+// would have expired on their own. Tokens can be revoked one by one (jti),
+// per session (sid) or for a user as a whole (everything issued before a
+// cut-off time).
+type TokenDenylistRepository struct {
+	Redis *redis.Client
+}
+
+func NewTokenDenylistRepository(client *redis.Client) *TokenDenylistRepository {
+	return &TokenDenylistRepository{Redis: client}
+}
+
+func (r *TokenDenylistRepository) RevokeToken(ctx context.Context, jti string, ttl time.Duration) error {
+	if jti == "" || ttl <= 0 {
+		return nil
+	}
+	if err := r.Redis.Set(ctx, denylistTokenKey(jti), 1, ttl).Err(); err != nil {
+		logger.Log.Error("Error revoking token", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *TokenDenylistRepository) RevokeSessions(ctx context.Context, sessionIDs []string, ttl time.Duration) error {
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+
+	pipe := r.Redis.TxPipeline()
+	for _, id := range sessionIDs {
+		pipe.Set(ctx, denylistSessionKey(id), 1, ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Log.Error("Error revoking session tokens", "error", err)
+		return err
+	}
+	return nil
+}
+
+// RevokeUser rejects every token of the user issued at or before the given
+// time. The cut-off is kept in seconds with microseconds, the precision of
+// the "iat" claim, so that tokens issued later in the same second stay valid.
+func (r *TokenDenylistRepository) RevokeUser(ctx context.Context, userID int, before time.Time, ttl time.Duration) error {
+	cutoff := strconv.FormatFloat(float64(before.UnixMicro())/1e6, 'f', 6, 64)
+	if err := r.Redis.Set(ctx, denylistUserKey(userID), cutoff, ttl).Err(); err != nil {
+		logger.Log.Error("Error revoking user tokens", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *TokenDenylistRepository) IsRevoked(ctx context.Context, jti, sessionID string, userID int, issuedAt time.Time) (bool, error) {
+	keys := []string{}
+	if jti != "" {
+		keys = append(keys, denylistTokenKey(jti))
+	}
+	if sessionID != "" {
+		keys = append(keys, denylistSessionKey(sessionID))
+	}
+
+	pipe := r.Redis.Pipeline()
+	var exists *redis.IntCmd
+	if len(keys) > 0 {
+		exists = pipe.Exists(ctx, keys...)
+	}
+	cutoff := pipe.Get(ctx, denylistUserKey(userID))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		logger.Log.Error("Error checking token denylist", "error", err)
+		return false, err
+	}
+
+	if exists != nil && exists.Val() > 0 {
+		return true, nil
+	}
+	if before, err := cutoff.Float64(); err == nil && issuedAt.UnixMicro() <= int64(math.Round(before*1e6)) {
+		return true, nil
+	}
+	return false, nil
+}
+
+func denylistTokenKey(jti string) string {
+	return "denylist:jti:" + jti
+}
+
+func denylistSessionKey(sessionID string) string {
+	return "denylist:sid:" + sessionID
+}
+
+func denylistUserKey(userID int) string {
+	return "denylist:user:" + strconv.Itoa(userID)
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"news-api/internal/models"
+	"news-api/pkg/logger"
+)
+
+type UserIdentityRepository struct {
+	DB *sql.DB
+}
+
+func NewUserIdentityRepository(db *sql.DB) *UserIdentityRepository {
+	return &UserIdentityRepository{DB: db}
+}
+
+func (r *UserIdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (user_id, provider, subject, email, last_login_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		RETURNING id, created_at
+	`
+	err := r.DB.QueryRowContext(ctx, query, identity.UserID, identity.Provider, identity.Subject, identity.Email).
+		Scan(&identity.ID, &identity.CreatedAt)
+	if err != nil {
+		logger.Log.Error("Error creating user identity", "error", err)
+		return err
+	}
+	return nil
+}
+
+// Get returns the identity of the subject at the provider, or nil if it has
+// not been linked to a user yet.
+func (r *UserIdentityRepository) Get(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	identity := &models.UserIdentity{}
+	query := `
+		SELECT id, user_id, provider, subject, COALESCE(email, ''), created_at, last_login_at
+		FROM user_identities
+		WHERE provider=$1 AND subject=$2
+	`
+	err := r.DB.QueryRowContext(ctx, query, provider, subject).Scan(
+		&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject,
+		&identity.Email, &identity.CreatedAt, &identity.LastLoginAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		logger.Log.Error("Error fetching user identity", "error", err)
+		return nil, err
+	}
+	return identity, nil
+}
+
+// TouchLogin records a login through the identity and the email the provider
+// reported for it.
+func (r *UserIdentityRepository) TouchLogin(ctx context.Context, id int, email string) error {
+	query := `UPDATE user_identities SET email=$1, last_login_at=NOW() WHERE id=$2`
+	if _, err := r.DB.ExecContext(ctx, query, email, id); err != nil {
+		logger.Log.Error("Error updating user identity", "error", err)
+		return err
+	}
+	return nil
+}
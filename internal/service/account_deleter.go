@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	"news-api/pkg/logger"
+	"strconv"
+	"time"
+)
+
+const deletionBatchSize = 100
+
+// AccountDeleter erases accounts: it ends their sessions, deletes the user
+// together with what cascades from it and removes the avatar files. It also
+// carries out self-service deletions once their grace period is over.
+type AccountDeleter struct {
+	userRepo    interfaces.UserRepository
+	authService *AuthService
+	avatars     *AvatarService
+	audit       *AuditLog
+	// What scheduled deletions do with the news of the user.
+	newsAction string
+	transferTo int
+}
+
+func NewAccountDeleter(
+	userRepo interfaces.UserRepository,
+	authService *AuthService,
+	avatars *AvatarService,
+	audit *AuditLog,
+	newsAction string,
+	transferTo int,
+) *AccountDeleter {
+	return &AccountDeleter{
+		userRepo:    userRepo,
+		authService: authService,
+		avatars:     avatars,
+		audit:       audit,
+		newsAction:  newsAction,
+		transferTo:  transferTo,
+	}
+}
+
+func (d *AccountDeleter) Delete(ctx context.Context, u *models.User, newsAction string, transferTo int) error {
+	if err := d.authService.RevokeAllUserTokens(ctx, u.ID); err != nil {
+		return err
+	}
+	if err := d.userRepo.Delete(ctx, u.ID, newsAction, transferTo); err != nil {
+		return err
+	}
+	if u.AvatarKey != "" {
+		d.avatars.deleteVariants(ctx, u.AvatarKey)
+	}
+	return nil
+}
+
+// Run deletes the accounts whose grace period is over, checking every
+// interval until ctx is cancelled.
+func (d *AccountDeleter) Run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.DeleteDue(ctx); err != nil {
+				logger.Log.Error("Scheduled account deletion failed", "error", err)
+			}
+		}
+	}
+}
+
+func (d *AccountDeleter) DeleteDue(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	users, err := d.userRepo.ListDueForDeletion(ctx, time.Now(), deletionBatchSize)
+	if err != nil {
+		return err
+	}
+	for i := range users {
+		u := &users[i]
+		// One broken account must not hold up the others.
+		if err := d.Delete(ctx, u, d.newsAction, d.transferTo); err != nil {
+			logger.Log.Error("Failed to delete account", "user_id", u.ID, "error", err)
+			continue
+		}
+		logger.Log.Info("Account deleted after grace period", "user_id", u.ID, "news", d.newsAction)
+		d.audit.Success(ctx, models.Actor{}, models.AuditUserDelete, models.AuditTargetUser, strconv.Itoa(u.ID),
+			"grace period over, news: "+d.newsAction)
+	}
+	return nil
+}
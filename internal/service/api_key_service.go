@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"news-api/internal/dto/apikey"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	"news-api/pkg/logger"
+	"news-api/pkg/token"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	apiKeyDisplayLength  = 12
+	maxAPIKeysPerUser    = 20
+	defaultAPIKeyDays    = 90
+	maxAPIKeyDays        = 365
+	apiKeyUsagePrecision = time.Minute
+)
+
+type APIKeyService struct {
+	keyRepo  interfaces.APIKeyRepository
+	userRepo interfaces.UserRepository
+}
+
+func NewAPIKeyService(keyRepo interfaces.APIKeyRepository, userRepo interfaces.UserRepository) *APIKeyService {
+	return &APIKeyService{keyRepo: keyRepo, userRepo: userRepo}
+}
+
+// Create mints a key for the user and returns it together with the plain key,
+// which is not stored and cannot be shown again.
+func (s *APIKeyService) Create(ctx context.Context, userID int, input apikey.CreateAPIKeyRequest) (*models.APIKey, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len(name) > 100 {
+		return nil, "", errors.Join(errors2.ErrValidation, errors.New("name is required and must be at most 100 characters"))
+	}
+	scopes, err := normalizeScopes(input.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	days := input.ExpiresInDays
+	if days == 0 {
+		days = defaultAPIKeyDays
+	}
+	if days < 0 || days > maxAPIKeyDays {
+		return nil, "", errors.Join(errors2.ErrValidation, fmt.Errorf("expires_in_days must be between 1 and %d", maxAPIKeyDays))
+	}
+
+	count, err := s.keyRepo.CountActive(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if count >= maxAPIKeysPerUser {
+		return nil, "", errors.Join(errors2.ErrValidation, fmt.Errorf("at most %d active API keys are allowed", maxAPIKeysPerUser))
+	}
+
+	secret, _, err := token.NewSecret()
+	if err != nil {
+		logger.Log.Error("Failed to generate API key: " + err.Error())
+		return nil, "", err
+	}
+	plain := models.APIKeyPrefix + secret
+
+	key := &models.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    plain[:apiKeyDisplayLength],
+		KeyHash:   token.HashSecret(plain),
+		Scopes:    scopes,
+		ExpiresAt: time.Now().AddDate(0, 0, days),
+	}
+	if err := s.keyRepo.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+
+	logger.Log.Info("API key created", "user_id", userID, "key_id", key.ID, "scopes", scopes)
+	return key, plain, nil
+}
+
+func (s *APIKeyService) List(ctx context.Context, userID int) ([]models.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return s.keyRepo.ListByUser(ctx, userID)
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, userID, id int) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	ok, err := s.keyRepo.Revoke(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors2.ErrNotFound
+	}
+	logger.Log.Info("API key revoked", "user_id", userID, "key_id", id)
+	return nil
+}
+
+// Authenticate returns the actor for a plain API key. The role is read from
+// the user on every request, so demotions apply to existing keys at once.
+func (s *APIKeyService) Authenticate(ctx context.Context, plain string) (*models.Actor, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if !strings.HasPrefix(plain, models.APIKeyPrefix) {
+		return nil, errors2.ErrUnauthorized
+	}
+	key, err := s.keyRepo.GetByHash(ctx, token.HashSecret(plain))
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || !key.Usable(time.Now()) {
+		return nil, errors2.ErrUnauthorized
+	}
+
+	user, err := s.userRepo.GetByID(key.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.Suspended() {
+		return nil, errors2.ErrUnauthorized
+	}
+
+	if err := s.keyRepo.TouchLastUsed(ctx, key.ID, apiKeyUsagePrecision); err != nil {
+		logger.Log.Warn("API key last use not recorded", "key_id", key.ID, "error", err)
+	}
+
+	return &models.Actor{
+		UserID:        user.ID,
+		Role:          user.Role,
+		EmailVerified: user.EmailVerified(),
+		APIKeyID:      key.ID,
+		Scopes:        key.Scopes,
+	}, nil
+}
+
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, errors.Join(errors2.ErrValidation, errors.New("at least one scope is required"))
+	}
+	out := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !slices.Contains(models.APIKeyScopes, scope) {
+			return nil, errors.Join(errors2.ErrValidation, fmt.Errorf("unknown scope %q", scope))
+		}
+		if !slices.Contains(out, scope) {
+			out = append(out, scope)
+		}
+	}
+	return out, nil
+}
+
+// requireScope refuses API keys that were not granted the scope.
+func requireScope(actor models.Actor, scope string) error {
+	if !actor.HasScope(scope) {
+		return errors.Join(errors2.ErrForbidden, fmt.Errorf("API key lacks scope %s", scope))
+	}
+	return nil
+}
@@ -2,41 +2,67 @@ package service
 
 import (
 	"context"
-	"fmt"
-	"github.com/redis/go-redis/v9"
+	"errors"
 	"log/slog"
 	"news-api/internal/dto/auth"
+	errors2 "news-api/internal/dto/errors"
 	"news-api/internal/models"
 	"news-api/internal/repository/interfaces"
 	"news-api/pkg/logger"
 	"news-api/pkg/password"
 	"news-api/pkg/token"
+	"slices"
 	"strconv"
 	"time"
 )
 
-const (
-	passwordLength = 6
-	contextTimeout = 5 * time.Second
-)
+const contextTimeout = 5 * time.Second
 
 type AuthService struct {
-	authRepo   interfaces.UserRepository
-	redis      *redis.Client
-	jwtManager *token.JWTManager
+	authRepo    interfaces.UserRepository
+	sessionRepo interfaces.SessionRepository
+	denylist    interfaces.TokenDenylistRepository
+	verifier    *VerificationService
+	guard       *LoginGuard
+	mfa         *MFAService
+	authz       *Authorizer
+	jwtManager  *token.JWTManager
+	policy      *password.Policy
+	audit       *AuditLog
 }
 
-func NewAuthService(repo interfaces.UserRepository, redis *redis.Client, jwtManager *token.JWTManager) *AuthService {
-	return &AuthService{authRepo: repo, redis: redis, jwtManager: jwtManager}
+func NewAuthService(
+	repo interfaces.UserRepository,
+	sessionRepo interfaces.SessionRepository,
+	denylist interfaces.TokenDenylistRepository,
+	verifier *VerificationService,
+	guard *LoginGuard,
+	mfa *MFAService,
+	authz *Authorizer,
+	jwtManager *token.JWTManager,
+	policy *password.Policy,
+	audit *AuditLog,
+) *AuthService {
+	return &AuthService{
+		authRepo:    repo,
+		sessionRepo: sessionRepo,
+		denylist:    denylist,
+		verifier:    verifier,
+		guard:       guard,
+		mfa:         mfa,
+		authz:       authz,
+		jwtManager:  jwtManager,
+		policy:      policy,
+		audit:       audit,
+	}
 }
 
 func (s *AuthService) Register(ctx context.Context, input auth.RegisterUserInput) error {
 	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
 	defer cancel()
 
-	if len(input.Password) < passwordLength {
-		err := fmt.Errorf("password must be at least 6 characters")
-		logger.Log.Error("Password validation failed: " + err.Error())
+	if err := s.checkNewPassword(input.Password, input.Email, input.FirstName, input.LastName); err != nil {
+		logger.Log.Warn("Password validation failed: " + err.Error())
 		return err
 	}
 
@@ -51,59 +77,504 @@ func (s *AuthService) Register(ctx context.Context, input auth.RegisterUserInput
 		LastName:  input.LastName,
 		Email:     input.Email,
 		Password:  hashedPassword,
-		Role:      "editor",
-		Avatar:    input.Avatar,
+		Role:      models.RoleEditor,
 		CreatedAt: time.Now(),
 	}
 
-	return s.authRepo.Create(ctx, &user)
+	if err := s.authRepo.Create(ctx, &user); err != nil {
+		return err
+	}
+
+	// The account exists even if the email cannot be sent right now; the user
+	// can ask for another link after logging in.
+	if err := s.verifier.SendVerification(ctx, &user, user.Email); err != nil {
+		logger.Log.Warn("Verification email not sent", slog.Int("user_id", user.ID), slog.String("error", err.Error()))
+	}
+	return nil
 }
 
-func (s *AuthService) Login(ctx context.Context, input auth.LoginUserInput) (string, string, error) {
+// Login checks the password and either starts a session or, when a second
+// factor is needed, returns the token of an MFA challenge instead.
+func (s *AuthService) Login(ctx context.Context, input auth.LoginUserInput, client models.ClientInfo) (*models.LoginResult, error) {
 	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
 	defer cancel()
 
+	if err := s.guard.Check(ctx, input.Email, client.IP); err != nil {
+		logger.Log.Warn("Login throttled", slog.String("email", input.Email), slog.String("ip", client.IP), slog.String("error", err.Error()))
+		s.auditLoginFailure(ctx, client, nil, input.Email, "throttled")
+		return nil, err
+	}
+
 	user, err := s.authRepo.GetByEmail(input.Email)
 	if err != nil {
 		logger.Log.Warn("Login failed: user not found", slog.String("email", input.Email), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var ok, outdated bool
+	if user != nil {
+		ok, outdated = password.CheckPassword(input.Password, user.Password)
+	}
+	if !ok {
+		logger.Log.Warn("Login failed: incorrect email or password", slog.String("email", input.Email))
+		s.auditLoginFailure(ctx, client, user, input.Email, "incorrect email or password")
+		if err := s.guard.Fail(ctx, input.Email, client.IP); err != nil {
+			logger.Log.Error("Failed to record login failure: " + err.Error())
+		}
+		return nil, errors2.ErrUnauthorized
+	}
+
+	if outdated {
+		s.rehashPassword(ctx, user, input.Password)
+	}
+
+	result, err := s.completeLogin(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	// Failures are only forgotten once a session is started; with MFA that is
+	// after the code is accepted, as wrong codes count as failures too.
+	if result.MFAToken == "" {
+		s.resetLoginFailures(ctx, user.Email)
+	}
+	return result, nil
+}
+
+func (s *AuthService) resetLoginFailures(ctx context.Context, email string) {
+	if err := s.guard.Succeed(ctx, email); err != nil {
+		logger.Log.Error("Failed to reset login failures: " + err.Error())
+	}
+}
+
+// rehashPassword replaces a hash made with old parameters while the plain
+// password is at hand. Failing to do so does not fail the login; the next
+// one tries again.
+func (s *AuthService) rehashPassword(ctx context.Context, user *models.User, plain string) {
+	hashed, err := password.HashPassword(plain)
+	if err == nil {
+		err = s.authRepo.UpdatePassword(ctx, user.ID, hashed)
+	}
+	if err != nil {
+		logger.Log.Error("Failed to upgrade password hash", "error", err, "user_id", user.ID)
+		return
+	}
+	user.Password = hashed
+	logger.Log.Info("Password hash upgraded", "user_id", user.ID)
+}
+
+// completeLogin starts a session for a user whose first factor was accepted,
+// or an MFA challenge if the user needs a second one.
+func (s *AuthService) completeLogin(ctx context.Context, user *models.User, client models.ClientInfo) (*models.LoginResult, error) {
+	if user.Suspended() {
+		logger.Log.Warn("Login refused: account suspended", slog.String("email", user.Email))
+		s.auditLoginFailure(ctx, client, user, user.Email, "account suspended")
+		return nil, errors2.ErrAccountSuspended
+	}
+
+	purpose, err := s.mfa.ChallengePurpose(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if purpose != "" {
+		mfaToken, err := s.mfa.StartChallenge(ctx, user.ID, purpose)
+		if err != nil {
+			logger.Log.Error("Failed to start MFA challenge: " + err.Error())
+			return nil, err
+		}
+		logger.Log.Info("Login requires MFA", slog.String("email", user.Email), slog.String("purpose", purpose))
+		return &models.LoginResult{MFAToken: mfaToken, MFAEnrollment: purpose == models.MFAPurposeEnroll}, nil
+	}
+
+	return s.startSession(ctx, user, client)
+}
+
+// CompleteMFALogin finishes a login started by Login with a TOTP or recovery
+// code. For an enrolment challenge the code confirms the new secret and the
+// recovery codes are returned together with the tokens.
+func (s *AuthService) CompleteMFALogin(ctx context.Context, mfaToken, code string, client models.ClientInfo) (*models.LoginResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	challenge, err := s.mfa.LoadChallenge(ctx, mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.authRepo.GetByID(challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors2.ErrInvalidToken
+	}
+	if user.Suspended() {
+		return nil, errors2.ErrAccountSuspended
+	}
+	if err := s.guard.Check(ctx, user.Email, client.IP); err != nil {
+		logger.Log.Warn("MFA login throttled", slog.Int("user_id", user.ID), slog.String("ip", client.IP), slog.String("error", err.Error()))
+		s.auditLoginFailure(ctx, client, user, user.Email, "throttled")
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	if challenge.Purpose == models.MFAPurposeEnroll {
+		recoveryCodes, err = s.mfa.Enable(ctx, user.ID, code)
+	} else {
+		var ok bool
+		ok, err = s.mfa.VerifyLogin(ctx, user, code)
+		if err == nil && !ok {
+			err = errors2.ErrInvalidMFACode
+		}
+	}
+	if errors.Is(err, errors2.ErrInvalidMFACode) {
+		logger.Log.Warn("MFA login failed: invalid code", slog.Int("user_id", user.ID))
+		s.auditLoginFailure(ctx, client, user, user.Email, "invalid MFA code")
+		if err := s.mfa.FailChallenge(ctx, mfaToken); err != nil {
+			logger.Log.Error("Failed to record MFA failure: " + err.Error())
+		}
+		// Wrong codes count towards the login lockout too, and are only reset
+		// by a completed login, so that a known password cannot be used to
+		// try codes through fresh challenges.
+		if err := s.guard.Fail(ctx, user.Email, client.IP); err != nil {
+			logger.Log.Error("Failed to record login failure: " + err.Error())
+		}
+		return nil, errors2.ErrInvalidMFACode
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mfa.EndChallenge(ctx, mfaToken); err != nil {
+		logger.Log.Error("Failed to delete MFA challenge: " + err.Error())
+		return nil, err
+	}
+
+	result, err := s.startSession(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	s.resetLoginFailures(ctx, user.Email)
+	result.RecoveryCodes = recoveryCodes
+	return result, nil
+}
+
+// MFAEnrollmentSetup starts TOTP enrolment for a user whose login is blocked
+// until MFA is enabled.
+func (s *AuthService) MFAEnrollmentSetup(ctx context.Context, mfaToken string) (*models.MFASetup, error) {
+	challenge, err := s.mfa.LoadChallenge(ctx, mfaToken)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.Purpose != models.MFAPurposeEnroll {
+		return nil, errors2.ErrMFAAlreadyEnabled
+	}
+	return s.mfa.Setup(ctx, challenge.UserID)
+}
+
+func (s *AuthService) startSession(ctx context.Context, user *models.User, client models.ClientInfo) (*models.LoginResult, error) {
+	sessionID, err := token.NewID()
+	if err != nil {
+		logger.Log.Error("Failed to generate session id: " + err.Error())
+		return nil, err
+	}
+
+	accessToken, refreshToken, err := s.jwtManager.GenerateTokens(identityOf(user, sessionID))
+	if err != nil {
+		logger.Log.Error("Failed to generate tokens: " + err.Error())
+		return nil, err
+	}
+
+	now := time.Now()
+	session := models.Session{
+		ID:           sessionID,
+		UserID:       user.ID,
+		RefreshToken: refreshToken,
+		UserAgent:    client.UserAgent,
+		IP:           client.IP,
+		CreatedAt:    now,
+		LastUsedAt:   now,
+	}
+	if err := s.sessionRepo.Create(ctx, &session, token.RefreshTokenTTL); err != nil {
+		logger.Log.Error("Failed to save session: " + err.Error())
+		return nil, err
+	}
+	logger.Log.Info("User logged in", slog.String("email", user.Email), slog.String("session_id", sessionID))
+	s.audit.Success(ctx, models.Actor{UserID: user.ID, SessionID: sessionID, Client: client},
+		models.AuditLogin, models.AuditTargetSession, sessionID, "")
+	return &models.LoginResult{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair. Presenting a token
+// that was already rotated is treated as theft and revokes the whole session.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string, client models.ClientInfo) (string, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	claims, err := s.jwtManager.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		logger.Log.Warn("Refresh failed: invalid token", slog.String("error", err.Error()))
+		return "", "", errors2.ErrUnauthorized
+	}
+
+	uidFloat, ok := claims["user_id"].(float64)
+	sessionID, _ := claims["sid"].(string)
+	if !ok || sessionID == "" {
+		logger.Log.Warn("Refresh failed: invalid token payload")
+		return "", "", errors2.ErrUnauthorized
+	}
+	userID := int(uidFloat)
+
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		logger.Log.Error("Failed to load session: " + err.Error())
 		return "", "", err
 	}
+	if session == nil || session.UserID != userID {
+		logger.Log.Warn("Refresh failed: session not found", slog.Int("user_id", userID))
+		return "", "", errors2.ErrUnauthorized
+	}
+	if session.RefreshToken != refreshToken {
+		return "", "", s.revokeStolenSession(ctx, userID, sessionID, client)
+	}
 
-	if !password.CheckPassword(input.Password, user.Password) {
-		logger.Log.Warn("Login failed: incorrect password", slog.String("email", input.Email))
+	user, err := s.authRepo.GetByID(userID)
+	if err != nil {
+		logger.Log.Error("Failed to load user for refresh: " + err.Error())
 		return "", "", err
 	}
+	if user == nil {
+		logger.Log.Warn("Refresh failed: user not found", slog.Int("user_id", userID))
+		return "", "", errors2.ErrUnauthorized
+	}
+	if user.Suspended() {
+		logger.Log.Warn("Refresh refused: account suspended", slog.Int("user_id", userID))
+		return "", "", errors2.ErrAccountSuspended
+	}
 
-	accessToken, refreshToken, err := s.jwtManager.GenerateTokens(user.ID, user.Role)
+	accessToken, newRefreshToken, err := s.jwtManager.GenerateTokens(identityOf(user, sessionID))
 	if err != nil {
 		logger.Log.Error("Failed to generate tokens: " + err.Error())
 		return "", "", err
 	}
 
-	if err := s.SaveRefreshToken(user.ID, refreshToken); err != nil {
-		logger.Log.Error("Failed to save refresh token: " + err.Error())
+	session.RefreshToken = newRefreshToken
+	session.IP = client.IP
+	session.UserAgent = client.UserAgent
+	session.LastUsedAt = time.Now()
+	rotated, err := s.sessionRepo.Rotate(ctx, session, refreshToken, token.RefreshTokenTTL)
+	if err != nil {
+		logger.Log.Error("Failed to rotate refresh token: " + err.Error())
 		return "", "", err
 	}
-	logger.Log.Info("User logged in", slog.String("email", input.Email))
-	return accessToken, refreshToken, nil
+	if !rotated {
+		return "", "", s.revokeStolenSession(ctx, userID, sessionID, client)
+	}
 
+	logger.Log.Info("Tokens refreshed", slog.Int("user_id", userID), slog.String("session_id", sessionID))
+	return accessToken, newRefreshToken, nil
 }
 
-func (s *AuthService) SaveRefreshToken(userID int, refreshToken string) error {
-	err := s.redis.Set(context.Background(), s.getRefreshTokenKey(userID), refreshToken, 24*time.Hour*7).Err()
+// Logout ends the current session and denylists the access token it was
+// called with. Tokens issued without a session id predate multi-device
+// sessions, so every session of the user is ended instead.
+func (s *AuthService) Logout(ctx context.Context, actor models.Actor) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.denylist.RevokeToken(ctx, actor.TokenID, time.Until(actor.TokenExpiresAt)); err != nil {
+		return err
+	}
+
+	if actor.SessionID == "" {
+		if err := s.RevokeAllUserTokens(ctx, actor.UserID); err != nil {
+			return err
+		}
+		s.audit.Success(ctx, actor, models.AuditLogout, models.AuditTargetUser, strconv.Itoa(actor.UserID), "all sessions")
+		return nil
+	}
+	if err := s.sessionRepo.Delete(ctx, actor.UserID, actor.SessionID); err != nil {
+		return err
+	}
+	s.audit.Success(ctx, actor, models.AuditLogout, models.AuditTargetSession, actor.SessionID, "")
+	return nil
+}
+
+// RevokeAllUserTokens ends every session of the user and rejects all access
+// tokens issued so far. Used when credentials change or an account is banned.
+func (s *AuthService) RevokeAllUserTokens(ctx context.Context, userID int) error {
+	if _, err := s.sessionRepo.DeleteByUser(ctx, userID, ""); err != nil {
+		logger.Log.Error("Revoke user sessions failed", "error", err, "user_id", userID)
+		return err
+	}
+	if err := s.denylist.RevokeUser(ctx, userID, time.Now(), s.jwtManager.AccessTokenTTL()); err != nil {
+		logger.Log.Error("Revoke user tokens failed", "error", err, "user_id", userID)
+		return err
+	}
+	logger.Log.Info("All user tokens revoked", "user_id", userID)
+	return nil
+}
+
+// changeRole gives the user another role and ends their sessions, since the
+// role is carried in access tokens. The last active user who can manage roles
+// cannot lose the permission. reason, if any, is added to the audit event.
+func (s *AuthService) changeRole(ctx context.Context, actor models.Actor, user *models.User, role, reason string) error {
+	managers, err := s.authz.RolesWith(ctx, models.PermRolesManage)
 	if err != nil {
 		return err
 	}
+	if !user.Suspended() && !slices.Contains(managers, role) {
+		if err := requireOtherRoleManager(ctx, s.authz, s.authRepo, user); err != nil {
+			return err
+		}
+	}
+
+	if err := s.authRepo.UpdateRole(ctx, user.ID, role); err != nil {
+		return err
+	}
+	if err := s.RevokeAllUserTokens(ctx, user.ID); err != nil {
+		return err
+	}
+
+	detail := user.Role + " -> " + role
+	if reason != "" {
+		detail += " (" + reason + ")"
+	}
+	logger.Log.Info("User role changed", "user_id", user.ID, "from", user.Role, "to", role, "actor_id", actor.UserID, "reason", reason)
+	s.audit.Success(ctx, actor, models.AuditUserRoleChange, models.AuditTargetUser, strconv.Itoa(user.ID), detail)
+	user.Role = role
 	return nil
 }
-func (s *AuthService) Logout(ctx context.Context, userID int) error {
+
+// UnlockUser lifts a login lockout of the user before it expires.
+func (s *AuthService) UnlockUser(ctx context.Context, actor models.Actor, userID int) error {
 	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
 	defer cancel()
 
-	key := s.getRefreshTokenKey(userID)
-	return s.redis.Del(ctx, key).Err()
+	if err := s.authz.Require(ctx, actor, models.PermUsersManage); err != nil {
+		return err
+	}
+
+	user, err := s.authRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors2.ErrNotFound
+	}
+
+	if err := s.guard.Unlock(ctx, user.Email); err != nil {
+		logger.Log.Error("Unlock user failed", "error", err, "user_id", userID)
+		return err
+	}
+	logger.Log.Info("User login unlocked", "user_id", userID, "admin_id", actor.UserID)
+	s.audit.Success(ctx, actor, models.AuditUserUnlock, models.AuditTargetUser, strconv.Itoa(userID), "")
+	return nil
 }
 
-func (s *AuthService) getRefreshTokenKey(userID int) string {
-	return "refresh_token:" + strconv.Itoa(userID)
+func (s *AuthService) ListSessions(ctx context.Context, userID int) ([]models.Session, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	sessions, err := s.sessionRepo.ListByUser(ctx, userID)
+	if err != nil {
+		logger.Log.Error("List sessions failed", "error", err, "user_id", userID)
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (s *AuthService) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		logger.Log.Error("GetByID before revoke failed", "error", err, "session_id", sessionID)
+		return err
+	}
+	if session == nil || session.UserID != userID {
+		return errors2.ErrNotFound
+	}
+
+	if err := s.endSessions(ctx, userID, sessionID); err != nil {
+		logger.Log.Error("Revoke session failed", "error", err, "session_id", sessionID)
+		return err
+	}
+	logger.Log.Info("Session revoked", "user_id", userID, "session_id", sessionID)
+	return nil
+}
+
+// RevokeOtherSessions logs the user out everywhere except currentSessionID.
+func (s *AuthService) RevokeOtherSessions(ctx context.Context, userID int, currentSessionID string) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	removed, err := s.sessionRepo.DeleteByUser(ctx, userID, currentSessionID)
+	if err != nil {
+		logger.Log.Error("Revoke other sessions failed", "error", err, "user_id", userID)
+		return err
+	}
+	if err := s.denylist.RevokeSessions(ctx, removed, s.jwtManager.AccessTokenTTL()); err != nil {
+		logger.Log.Error("Revoke other sessions tokens failed", "error", err, "user_id", userID)
+		return err
+	}
+	logger.Log.Info("Other sessions revoked", "user_id", userID, "kept_session_id", currentSessionID)
+	return nil
+}
+
+func (s *AuthService) revokeStolenSession(ctx context.Context, userID int, sessionID string, client models.ClientInfo) error {
+	logger.Log.Warn("Refresh token reuse detected, revoking session",
+		slog.Int("user_id", userID), slog.String("session_id", sessionID))
+	s.audit.Failure(ctx, models.Actor{Client: client}, models.AuditRefreshReuse, models.AuditTargetSession, sessionID,
+		"refresh token of user "+strconv.Itoa(userID)+" presented again; session revoked")
+	if err := s.endSessions(ctx, userID, sessionID); err != nil {
+		logger.Log.Error("Failed to revoke session: " + err.Error())
+		return err
+	}
+	return errors.Join(errors2.ErrUnauthorized, errors2.ErrTokenReused)
+}
+
+// auditLoginFailure records a refused login. The account is the target when
+// it is known; the email is kept in the detail for attempts on unknown ones.
+func (s *AuthService) auditLoginFailure(ctx context.Context, client models.ClientInfo, user *models.User, email, reason string) {
+	targetID := ""
+	if user != nil {
+		targetID = strconv.Itoa(user.ID)
+	}
+	s.audit.Failure(ctx, models.Actor{Client: client}, models.AuditLogin, models.AuditTargetUser, targetID, reason+" ("+email+")")
+}
+
+// endSessions deletes the sessions and denylists the access tokens issued
+// for them, so that they stop working before they expire.
+func (s *AuthService) endSessions(ctx context.Context, userID int, sessionIDs ...string) error {
+	for _, id := range sessionIDs {
+		if err := s.sessionRepo.Delete(ctx, userID, id); err != nil {
+			return err
+		}
+	}
+	return s.denylist.RevokeSessions(ctx, sessionIDs, s.jwtManager.AccessTokenTTL())
+}
+
+func identityOf(user *models.User, sessionID string) token.Identity {
+	return token.Identity{
+		UserID:        user.ID,
+		Role:          user.Role,
+		SessionID:     sessionID,
+		EmailVerified: user.EmailVerified(),
+	}
+}
+
+// checkNewPassword applies the password policy to a password being set.
+// personal is what the password must not contain: the email and names of the
+// account. Policy violations are validation errors.
+func (s *AuthService) checkNewPassword(pw string, personal ...string) error {
+	err := s.policy.Validate(pw, personal...)
+	var violation *password.PolicyError
+	if errors.As(err, &violation) {
+		return errors.Join(errors2.ErrValidation, err)
+	}
+	if err != nil {
+		logger.Log.Error("Password policy check failed", "error", err)
+	}
+	return err
 }
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	"news-api/pkg/logger"
+	"sync"
+	"time"
+)
+
+// rolePermissionsTTL bounds how long other replicas keep using permissions
+// after an admin changed a role.
+const rolePermissionsTTL = 30 * time.Second
+
+// Authorizer answers whether an actor holds a permission. It is the single
+// place that maps roles to permissions; services and middleware ask it
+// instead of comparing role names.
+type Authorizer struct {
+	roleRepo interfaces.RoleRepository
+
+	mu       sync.RWMutex
+	perms    map[string]map[string]bool
+	loadedAt time.Time
+}
+
+func NewAuthorizer(roleRepo interfaces.RoleRepository) *Authorizer {
+	return &Authorizer{roleRepo: roleRepo}
+}
+
+func (a *Authorizer) Can(ctx context.Context, actor models.Actor, permission string) (bool, error) {
+	perms, err := a.rolePermissions(ctx)
+	if err != nil {
+		return false, err
+	}
+	return perms[actor.Role][permission], nil
+}
+
+// Require returns ErrForbidden unless the actor holds the permission.
+func (a *Authorizer) Require(ctx context.Context, actor models.Actor, permission string) error {
+	ok, err := a.Can(ctx, actor, permission)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		logger.Log.Warn("Permission denied", "user_id", actor.UserID, "role", actor.Role, "permission", permission)
+		return errors.Join(errors2.ErrForbidden, fmt.Errorf("missing permission %s", permission))
+	}
+	return nil
+}
+
+// privilegedPermissions let their holders manage other users. Users holding
+// them can only be acted on by users holding them as well.
+var privilegedPermissions = []string{models.PermUsersManage, models.PermRolesManage, models.PermUsersImpersonate}
+
+// RequireOutranks returns ErrForbidden if the role holds a privileged
+// permission the actor does not.
+func (a *Authorizer) RequireOutranks(ctx context.Context, actor models.Actor, role string) error {
+	perms, err := a.rolePermissions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, p := range privilegedPermissions {
+		if perms[role][p] && !perms[actor.Role][p] {
+			logger.Log.Warn("Privileged target denied", "user_id", actor.UserID, "role", actor.Role, "target_role", role, "permission", p)
+			return errors.Join(errors2.ErrForbidden, fmt.Errorf("only users holding %s can manage users with role %s", p, role))
+		}
+	}
+	return nil
+}
+
+// Privileged reports whether the role holds any of privilegedPermissions.
+func (a *Authorizer) Privileged(ctx context.Context, role string) (bool, error) {
+	perms, err := a.rolePermissions(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range privilegedPermissions {
+		if perms[role][p] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RolesWith returns the roles that grant the permission.
+func (a *Authorizer) RolesWith(ctx context.Context, permission string) ([]string, error) {
+	perms, err := a.rolePermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var roles []string
+	for role, set := range perms {
+		if set[permission] {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+// Invalidate drops the cached role permissions after a role was changed.
+func (a *Authorizer) Invalidate() {
+	a.mu.Lock()
+	a.perms = nil
+	a.mu.Unlock()
+}
+
+func (a *Authorizer) rolePermissions(ctx context.Context) (map[string]map[string]bool, error) {
+	a.mu.RLock()
+	perms, loadedAt := a.perms, a.loadedAt
+	a.mu.RUnlock()
+	if perms != nil && time.Since(loadedAt) < rolePermissionsTTL {
+		return perms, nil
+	}
+
+	roles, err := a.roleRepo.List(ctx)
+	if err != nil {
+		logger.Log.Error("Failed to load role permissions", "error", err)
+		return nil, err
+	}
+	perms = make(map[string]map[string]bool, len(roles))
+	for _, role := range roles {
+		set := make(map[string]bool, len(role.Permissions))
+		for _, p := range role.Permissions {
+			set[p] = true
+		}
+		perms[role.Name] = set
+	}
+
+	a.mu.Lock()
+	a.perms, a.loadedAt = perms, time.Now()
+	a.mu.Unlock()
+	return perms, nil
+}
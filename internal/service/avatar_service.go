@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	"news-api/pkg/imageproc"
+	"news-api/pkg/logger"
+	"news-api/pkg/storage"
+	"slices"
+	"time"
+)
+
+const (
+	avatarQuality = 85
+	// Uploading to remote storage takes longer than a database query.
+	avatarTimeout = 30 * time.Second
+)
+
+var avatarContentTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// AvatarService stores profile pictures. Every upload is re-encoded into
+// the square models.AvatarVariants under a fresh key, so the URLs of an
+// avatar never change and can be cached forever.
+type AvatarService struct {
+	userRepo interfaces.UserRepository
+	store    storage.BlobStore
+	maxBytes int
+}
+
+func NewAvatarService(userRepo interfaces.UserRepository, store storage.BlobStore, maxBytes int) *AvatarService {
+	return &AvatarService{userRepo: userRepo, store: store, maxBytes: maxBytes}
+}
+
+// Upload replaces the avatar of the user and returns the URLs of its
+// variants by name.
+func (s *AvatarService) Upload(ctx context.Context, userID int, data []byte) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, avatarTimeout)
+	defer cancel()
+
+	if len(data) > s.maxBytes {
+		return nil, errors2.ErrFileTooLarge
+	}
+	if !slices.Contains(avatarContentTypes, http.DetectContentType(data)) {
+		return nil, errors.Join(errors2.ErrValidation, errors.New("avatar must be a JPEG, PNG, GIF or WebP image"))
+	}
+	img, err := imageproc.Decode(data)
+	if err != nil {
+		return nil, errors.Join(errors2.ErrValidation, err)
+	}
+
+	u, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, errors2.ErrNotFound
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	base := fmt.Sprintf("avatars/%d/%s", u.ID, hex.EncodeToString(suffix))
+
+	urls := make(map[string]string, len(models.AvatarVariants))
+	for _, v := range models.AvatarVariants {
+		encoded, err := imageproc.EncodeJPEG(imageproc.Square(img, v.Size), avatarQuality)
+		if err != nil {
+			return nil, err
+		}
+		key := models.AvatarVariantKey(base, v.Name)
+		if err := s.store.Put(ctx, key, "image/jpeg", encoded); err != nil {
+			logger.Log.Error("Failed to store avatar", "error", err, "key", key)
+			s.deleteVariants(ctx, base)
+			return nil, err
+		}
+		urls[v.Name] = s.store.URL(key)
+	}
+
+	if err := s.userRepo.SetAvatar(ctx, u.ID, urls[models.DefaultAvatarVariant], base); err != nil {
+		s.deleteVariants(ctx, base)
+		return nil, err
+	}
+	if u.AvatarKey != "" {
+		s.deleteVariants(ctx, u.AvatarKey)
+	}
+
+	logger.Log.Info("Avatar uploaded", "user_id", u.ID, "key", base)
+	return urls, nil
+}
+
+func (s *AvatarService) Remove(ctx context.Context, userID int) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	u, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return errors2.ErrNotFound
+	}
+
+	if err := s.userRepo.SetAvatar(ctx, u.ID, "", ""); err != nil {
+		return err
+	}
+	if u.AvatarKey != "" {
+		s.deleteVariants(ctx, u.AvatarKey)
+	}
+
+	logger.Log.Info("Avatar removed", "user_id", u.ID)
+	return nil
+}
+
+// deleteVariants removes the files of an avatar. Failures only leave
+// unreferenced files behind, so they are logged and otherwise ignored.
+func (s *AvatarService) deleteVariants(ctx context.Context, base string) {
+	for _, v := range models.AvatarVariants {
+		key := models.AvatarVariantKey(base, v.Name)
+		if err := s.store.Delete(ctx, key); err != nil {
+			logger.Log.Warn("Failed to delete avatar file", "key", key, "error", err)
+		}
+	}
+}
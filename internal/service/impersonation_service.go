@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	"news-api/pkg/logger"
+	"news-api/pkg/token"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxImpersonationReasonLength = 500
+
+// ImpersonationService lets support staff see the service as a given user.
+// The token it issues is short-lived, cannot be refreshed and names the admin
+// in its "act" claim; every impersonation is recorded.
+type ImpersonationService struct {
+	userRepo   interfaces.UserRepository
+	impRepo    interfaces.ImpersonationRepository
+	authz      *Authorizer
+	jwtManager *token.JWTManager
+	ttl        time.Duration
+	audit      *AuditLog
+}
+
+func NewImpersonationService(
+	userRepo interfaces.UserRepository,
+	impRepo interfaces.ImpersonationRepository,
+	authz *Authorizer,
+	jwtManager *token.JWTManager,
+	ttl time.Duration,
+	audit *AuditLog,
+) *ImpersonationService {
+	return &ImpersonationService{
+		userRepo:   userRepo,
+		impRepo:    impRepo,
+		authz:      authz,
+		jwtManager: jwtManager,
+		ttl:        ttl,
+		audit:      audit,
+	}
+}
+
+// Impersonate issues an access token acting as the user. Admins and other
+// privileged users cannot be impersonated, so the token never grants more
+// than the impersonating admin already has.
+func (s *ImpersonationService) Impersonate(ctx context.Context, actor models.Actor, userID int, reason string, client models.ClientInfo) (string, *models.Impersonation, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermUsersImpersonate); err != nil {
+		return "", nil, err
+	}
+	if actor.Impersonated() {
+		return "", nil, errors.Join(errors2.ErrForbidden, errors.New("impersonation tokens cannot start another impersonation"))
+	}
+	if userID == actor.UserID {
+		return "", nil, errors.Join(errors2.ErrValidation, errors.New("you cannot impersonate yourself"))
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", nil, errors.Join(errors2.ErrValidation, errors.New("reason is required"))
+	}
+	if len(reason) > maxImpersonationReasonLength {
+		return "", nil, errors.Join(errors2.ErrValidation, errors.New("reason is too long"))
+	}
+
+	u, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return "", nil, err
+	}
+	if u == nil {
+		return "", nil, errors2.ErrNotFound
+	}
+	if u.Suspended() {
+		return "", nil, errors.Join(errors2.ErrValidation, errors.New("suspended users cannot be impersonated"))
+	}
+	if err := s.requireUnprivileged(ctx, u); err != nil {
+		return "", nil, err
+	}
+
+	// A session id of its own lets logout revoke the token without touching
+	// the real sessions of the user.
+	sessionID, err := token.NewID()
+	if err != nil {
+		return "", nil, err
+	}
+	id := identityOf(u, sessionID)
+	id.ImpersonatorID = actor.UserID
+
+	accessToken, tokenID, err := s.jwtManager.GenerateAccessToken(id, s.ttl)
+	if err != nil {
+		logger.Log.Error("Failed to generate impersonation token", "error", err)
+		return "", nil, err
+	}
+
+	imp := &models.Impersonation{
+		AdminID:   actor.UserID,
+		UserID:    u.ID,
+		Reason:    reason,
+		TokenID:   tokenID,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+	// No audit record, no token.
+	if err := s.impRepo.Create(ctx, imp); err != nil {
+		return "", nil, err
+	}
+
+	logger.Log.Warn("Impersonation started",
+		"admin_id", actor.UserID, "user_id", u.ID, "reason", reason, "ip", client.IP, "expires_at", imp.ExpiresAt,
+	)
+	s.audit.Success(ctx, actor, models.AuditUserImpersonate, models.AuditTargetUser, strconv.Itoa(u.ID), reason)
+	return accessToken, imp, nil
+}
+
+func (s *ImpersonationService) requireUnprivileged(ctx context.Context, u *models.User) error {
+	privileged, err := s.authz.Privileged(ctx, u.Role)
+	if err != nil {
+		return err
+	}
+	if privileged {
+		return errors.Join(errors2.ErrForbidden, errors.New("privileged users cannot be impersonated"))
+	}
+	return nil
+}
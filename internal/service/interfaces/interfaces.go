@@ -2,20 +2,128 @@ package interfaces
 
 import (
 	"context"
+	"news-api/internal/dto/apikey"
 	"news-api/internal/dto/auth"
+	"news-api/internal/dto/role"
+	"news-api/internal/dto/taxonomy"
+	"news-api/internal/dto/user"
 	"news-api/internal/models"
+	"time"
 )
 
 type AuthService interface {
 	Register(ctx context.Context, input auth.RegisterUserInput) error
-	Login(ctx context.Context, input auth.LoginUserInput) (string, string, error)
-	Logout(ctx context.Context, userID int) error
+	Login(ctx context.Context, input auth.LoginUserInput, client models.ClientInfo) (*models.LoginResult, error)
+	CompleteMFALogin(ctx context.Context, mfaToken, code string, client models.ClientInfo) (*models.LoginResult, error)
+	MFAEnrollmentSetup(ctx context.Context, mfaToken string) (*models.MFASetup, error)
+	Refresh(ctx context.Context, refreshToken string, client models.ClientInfo) (string, string, error)
+	Logout(ctx context.Context, actor models.Actor) error
+	ListSessions(ctx context.Context, userID int) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID int, sessionID string) error
+	RevokeOtherSessions(ctx context.Context, userID int, currentSessionID string) error
+	UnlockUser(ctx context.Context, actor models.Actor, userID int) error
+}
+
+type ProfileService interface {
+	GetProfile(ctx context.Context, userID int) (*models.User, error)
+	UpdateProfile(ctx context.Context, userID int, input user.UpdateProfileRequest) (*models.User, error)
+	ChangePassword(ctx context.Context, actor models.Actor, input user.ChangePasswordRequest, client models.ClientInfo) error
+	RequestEmailChange(ctx context.Context, userID int, input user.ChangeEmailRequest, client models.ClientInfo) error
+	Export(ctx context.Context, userID int) (*models.UserDataExport, error)
+	RequestDeletion(ctx context.Context, userID int, password string, client models.ClientInfo) (time.Time, error)
+	CancelDeletion(ctx context.Context, userID int) error
+}
+
+type AvatarService interface {
+	Upload(ctx context.Context, userID int, data []byte) (map[string]string, error)
+	Remove(ctx context.Context, userID int) error
+}
+
+type PasswordService interface {
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, resetToken, newPassword string) error
+}
+
+type MagicLinkService interface {
+	SendLink(ctx context.Context, email string) error
+	Login(ctx context.Context, linkToken string, client models.ClientInfo) (*models.LoginResult, error)
+}
+
+type MFAService interface {
+	Setup(ctx context.Context, userID int) (*models.MFASetup, error)
+	Enable(ctx context.Context, userID int, code string) ([]string, error)
+	Disable(ctx context.Context, userID int, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID int, code string) ([]string, error)
+}
+
+type APIKeyService interface {
+	Create(ctx context.Context, userID int, input apikey.CreateAPIKeyRequest) (*models.APIKey, string, error)
+	List(ctx context.Context, userID int) ([]models.APIKey, error)
+	Revoke(ctx context.Context, userID, id int) error
+	Authenticate(ctx context.Context, plain string) (*models.Actor, error)
+}
+
+type Authorizer interface {
+	Can(ctx context.Context, actor models.Actor, permission string) (bool, error)
+}
+
+type RoleService interface {
+	ListRoles(ctx context.Context, actor models.Actor) ([]models.Role, error)
+	ListPermissions(ctx context.Context, actor models.Actor) ([]models.Permission, error)
+	CreateRole(ctx context.Context, actor models.Actor, input role.CreateRoleRequest) (*models.Role, error)
+	UpdateRole(ctx context.Context, actor models.Actor, name string, input role.UpdateRoleRequest) (*models.Role, error)
+	DeleteRole(ctx context.Context, actor models.Actor, name string) error
+	AssignRole(ctx context.Context, actor models.Actor, userID int, roleName string) error
+}
+
+type TaxonomyService interface {
+	ListCategories(ctx context.Context) ([]models.Category, error)
+	CreateCategory(ctx context.Context, actor models.Actor, input taxonomy.CategoryRequest) (*models.Category, error)
+	UpdateCategory(ctx context.Context, actor models.Actor, id int, input taxonomy.CategoryRequest) (*models.Category, error)
+	DeleteCategory(ctx context.Context, actor models.Actor, id int) error
+	ListTags(ctx context.Context) ([]models.Tag, error)
+	CreateTag(ctx context.Context, actor models.Actor, input taxonomy.TagRequest) (*models.Tag, error)
+	RenameTag(ctx context.Context, actor models.Actor, id int, input taxonomy.TagRequest) (*models.Tag, error)
+	DeleteTag(ctx context.Context, actor models.Actor, id int) error
+}
+
+type UserAdminService interface {
+	ListUsers(ctx context.Context, actor models.Actor, params models.UserListParams) ([]models.User, int, error)
+	GetUser(ctx context.Context, actor models.Actor, userID int) (*models.User, error)
+	SuspendUser(ctx context.Context, actor models.Actor, userID int, reason string) error
+	UnsuspendUser(ctx context.Context, actor models.Actor, userID int) error
+	DeleteUser(ctx context.Context, actor models.Actor, userID int, input user.DeleteUserInput) error
+}
+
+type ImpersonationService interface {
+	Impersonate(ctx context.Context, actor models.Actor, userID int, reason string, client models.ClientInfo) (string, *models.Impersonation, error)
+}
+
+type AuditService interface {
+	ListEvents(ctx context.Context, actor models.Actor, params models.AuditListParams) ([]models.AuditEvent, int, error)
+	ExportEvents(ctx context.Context, actor models.Actor, params models.AuditListParams) ([]models.AuditEvent, int, error)
+}
+
+type OIDCService interface {
+	AuthURL(ctx context.Context) (string, error)
+	Callback(ctx context.Context, code, state string, client models.ClientInfo) (*models.LoginResult, error)
+}
+
+type VerificationService interface {
+	VerifyEmail(ctx context.Context, verificationToken string) error
+	ResendVerification(ctx context.Context, userID int) error
 }
 
 type NewsService interface {
 	CreateNews(ctx context.Context, actor models.Actor, n *models.News) error
 	UpdateNews(ctx context.Context, actor models.Actor, n *models.News) error
 	DeleteNews(ctx context.Context, actor models.Actor, id int) error
-	GetByIDNews(ctx context.Context, id int) (*models.News, error)
-	ListNews(ctx context.Context, p models.NewsListParams) ([]models.News, error)
+	ChangeStatus(ctx context.Context, actor models.Actor, id int, change models.NewsStatusChange) (*models.News, error)
+	GetByIDNews(ctx context.Context, actor models.Actor, id int) (*models.News, error)
+	ListNews(ctx context.Context, actor models.Actor, p models.NewsListParams) ([]models.News, error)
+	ListRevisions(ctx context.Context, actor models.Actor, id int) ([]models.NewsRevision, error)
+	DiffRevisions(ctx context.Context, actor models.Actor, id, from, to int) (*models.NewsRevisionDiff, error)
+	RestoreRevision(ctx context.Context, actor models.Actor, id, revision int) (*models.News, error)
+	ListTrash(ctx context.Context, actor models.Actor, p models.NewsListParams) ([]models.News, int, error)
+	RestoreNews(ctx context.Context, actor models.Actor, id int) (*models.News, error)
 }
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/repository/interfaces"
+	"news-api/pkg/logger"
+	"strings"
+	"time"
+)
+
+const maxLoginBackoff = 15 * time.Minute
+
+// LoginPolicy configures how failed logins are throttled.
+type LoginPolicy struct {
+	// Window is how long failed attempts are remembered.
+	Window time.Duration
+	// BackoffAfter is the number of failures per email after which every
+	// further failure doubles the wait before the next attempt.
+	BackoffAfter int64
+	BackoffBase  time.Duration
+	// LockAfter is the number of failures per email that locks it for
+	// LockDuration.
+	LockAfter    int64
+	LockDuration time.Duration
+	// IPBackoffAfter is the number of failures from one IP, across all
+	// emails, after which the IP is slowed down.
+	IPBackoffAfter int64
+}
+
+// LoginGuard protects password checks against brute force.
+type LoginGuard struct {
+	attempts interfaces.LoginAttemptRepository
+	policy   LoginPolicy
+}
+
+func NewLoginGuard(attempts interfaces.LoginAttemptRepository, policy LoginPolicy) *LoginGuard {
+	return &LoginGuard{attempts: attempts, policy: policy}
+}
+
+// Check returns a RetryAfterError if a login for the email from the IP must
+// not be attempted right now.
+func (g *LoginGuard) Check(ctx context.Context, email, ip string) error {
+	email = normalizeEmail(email)
+
+	locked, err := g.attempts.LockedFor(ctx, email)
+	if err != nil {
+		return err
+	}
+	if locked > 0 {
+		return &errors2.RetryAfterError{Err: errors2.ErrAccountLocked, RetryAfter: locked}
+	}
+
+	blocked, err := g.attempts.BlockedFor(ctx, email, ip)
+	if err != nil {
+		return err
+	}
+	if blocked > 0 {
+		return &errors2.RetryAfterError{Err: errors2.ErrTooManyAttempts, RetryAfter: blocked}
+	}
+	return nil
+}
+
+// Fail records a failed login and applies backoff or lockout.
+func (g *LoginGuard) Fail(ctx context.Context, email, ip string) error {
+	email = normalizeEmail(email)
+
+	emailFails, ipFails, err := g.attempts.RecordFailure(ctx, email, ip, g.policy.Window)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case emailFails >= g.policy.LockAfter:
+		logger.Log.Warn("Login locked after repeated failures", "email", email, "failures", emailFails)
+		if err := g.attempts.LockEmail(ctx, email, g.policy.LockDuration); err != nil {
+			return err
+		}
+	case emailFails >= g.policy.BackoffAfter:
+		if err := g.attempts.BlockEmail(ctx, email, g.backoff(emailFails-g.policy.BackoffAfter)); err != nil {
+			return err
+		}
+	}
+
+	if ipFails >= g.policy.IPBackoffAfter {
+		logger.Log.Warn("Login throttled for IP", "ip", ip, "failures", ipFails)
+		if err := g.attempts.BlockIP(ctx, ip, g.backoff(ipFails-g.policy.IPBackoffAfter)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Succeed forgets earlier failures of the email.
+func (g *LoginGuard) Succeed(ctx context.Context, email string) error {
+	return g.attempts.Reset(ctx, normalizeEmail(email))
+}
+
+// Unlock removes a lockout of the email before it expires.
+func (g *LoginGuard) Unlock(ctx context.Context, email string) error {
+	return g.attempts.Reset(ctx, normalizeEmail(email))
+}
+
+func (g *LoginGuard) backoff(step int64) time.Duration {
+	if step > 20 {
+		return maxLoginBackoff
+	}
+	return min(g.policy.BackoffBase<<step, maxLoginBackoff)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	"news-api/pkg/logger"
+	"news-api/pkg/mailer"
+	"news-api/pkg/token"
+	"time"
+)
+
+// magicLinkCooldown is how long after a link was sent no further link is
+// sent to the same account.
+const magicLinkCooldown = time.Minute
+
+// MagicLinkService logs users in without a password through a signed,
+// single-use link sent to their email.
+type MagicLinkService struct {
+	userRepo    interfaces.UserRepository
+	linkRepo    interfaces.MagicLinkRepository
+	authService *AuthService
+	jwtManager  *token.JWTManager
+	mailer      mailer.Mailer
+	frontendURL string
+	ttl         time.Duration
+}
+
+func NewMagicLinkService(
+	userRepo interfaces.UserRepository,
+	linkRepo interfaces.MagicLinkRepository,
+	authService *AuthService,
+	jwtManager *token.JWTManager,
+	mailer mailer.Mailer,
+	frontendURL string,
+	ttl time.Duration,
+) *MagicLinkService {
+	return &MagicLinkService{
+		userRepo:    userRepo,
+		linkRepo:    linkRepo,
+		authService: authService,
+		jwtManager:  jwtManager,
+		mailer:      mailer,
+		frontendURL: frontendURL,
+		ttl:         ttl,
+	}
+}
+
+// SendLink emails a login link if an account with the email exists. Unknown
+// and suspended accounts are not reported so that accounts cannot be
+// enumerated, and repeated requests within magicLinkCooldown are dropped.
+func (s *MagicLinkService) SendLink(ctx context.Context, email string) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		logger.Log.Error("Magic link: user lookup failed", "error", err)
+		return err
+	}
+	if user == nil || user.Suspended() {
+		logger.Log.Info("Magic link not sent", slog.String("email", email))
+		return nil
+	}
+
+	ok, err := s.linkRepo.StartCooldown(ctx, user.ID, magicLinkCooldown)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		logger.Log.Info("Magic link not sent: cooldown", "user_id", user.ID)
+		return nil
+	}
+
+	nonce, err := token.NewID()
+	if err != nil {
+		return err
+	}
+	signed, err := s.jwtManager.GenerateMagicLinkToken(user.ID, nonce, s.ttl)
+	if err != nil {
+		logger.Log.Error("Failed to sign magic link: " + err.Error())
+		return err
+	}
+	link := models.MagicLink{UserID: user.ID, Email: user.Email}
+	if err := s.linkRepo.Save(ctx, token.HashSecret(nonce), link, s.ttl); err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Your login link",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nTo log in open the link below. It is valid for %d minutes and can be used once.\n\n%s\n\nIf you did not try to log in, ignore this email.\n",
+			user.FirstName, int(s.ttl.Minutes()), s.frontendURL+"/magic-login?token="+url.QueryEscape(signed),
+		),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		logger.Log.Error("Failed to send magic link email", "error", err, "user_id", user.ID)
+		return err
+	}
+
+	logger.Log.Info("Magic link sent", "user_id", user.ID)
+	return nil
+}
+
+// Login redeems a link sent by SendLink. The result is the same as that of
+// AuthService.Login: a token pair, or an MFA challenge if the account needs
+// a second factor.
+func (s *MagicLinkService) Login(ctx context.Context, linkToken string, client models.ClientInfo) (*models.LoginResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	userID, nonce, err := s.jwtManager.ValidateMagicLinkToken(linkToken)
+	if err != nil {
+		logger.Log.Warn("Magic link rejected", "error", err)
+		return nil, errors2.ErrInvalidToken
+	}
+
+	link, err := s.linkRepo.Consume(ctx, token.HashSecret(nonce))
+	if err != nil {
+		return nil, err
+	}
+	if link == nil || link.UserID != userID {
+		logger.Log.Warn("Magic link already used or unknown", "user_id", userID)
+		return nil, errors2.ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.Email != link.Email {
+		logger.Log.Warn("Magic link for a changed or deleted account", "user_id", userID)
+		return nil, errors2.ErrInvalidToken
+	}
+
+	logger.Log.Info("Magic link redeemed", "user_id", user.ID)
+	return s.authService.completeLogin(ctx, user, client)
+}
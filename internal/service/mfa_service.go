@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	"news-api/pkg/logger"
+	"news-api/pkg/token"
+	"news-api/pkg/totp"
+	"strings"
+	"time"
+)
+
+const (
+	mfaChallengeTTL    = 5 * time.Minute
+	mfaMaxAttempts     = 5
+	totpSkew           = 1
+	totpStepTTL        = (2*totpSkew + 2) * totp.Period * time.Second
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// MFAService manages TOTP enrolment, recovery codes and the second step of
+// the login.
+type MFAService struct {
+	userRepo         interfaces.UserRepository
+	recoveryRepo     interfaces.RecoveryCodeRepository
+	challengeRepo    interfaces.MFAChallengeRepository
+	authz            *Authorizer
+	issuer           string
+	requiredForAdmin bool
+}
+
+func NewMFAService(
+	userRepo interfaces.UserRepository,
+	recoveryRepo interfaces.RecoveryCodeRepository,
+	challengeRepo interfaces.MFAChallengeRepository,
+	authz *Authorizer,
+	issuer string,
+	requiredForAdmin bool,
+) *MFAService {
+	return &MFAService{
+		userRepo:         userRepo,
+		recoveryRepo:     recoveryRepo,
+		challengeRepo:    challengeRepo,
+		authz:            authz,
+		issuer:           issuer,
+		requiredForAdmin: requiredForAdmin,
+	}
+}
+
+// Setup generates a new TOTP secret for the user. It takes effect only after
+// Enable confirms that the authenticator app produces valid codes.
+func (s *MFAService) Setup(ctx context.Context, userID int) (*models.MFASetup, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled() {
+		return nil, errors2.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		logger.Log.Error("Failed to generate TOTP secret: " + err.Error())
+		return nil, err
+	}
+	if err := s.userRepo.SetMFASecret(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+
+	logger.Log.Info("MFA setup started", "user_id", userID)
+	return &models.MFASetup{Secret: secret, ProvisioningURI: totp.ProvisioningURI(s.issuer, user.Email, secret)}, nil
+}
+
+// Enable confirms the secret from Setup with a current code and returns a
+// fresh set of recovery codes.
+func (s *MFAService) Enable(ctx context.Context, userID int, code string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled() {
+		return nil, errors2.ErrMFAAlreadyEnabled
+	}
+	if user.MFASecret == "" {
+		return nil, errors.Join(errors2.ErrValidation, errors.New("MFA setup has not been started"))
+	}
+
+	ok, err := s.verify(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors2.ErrInvalidMFACode
+	}
+
+	if err := s.userRepo.EnableMFA(ctx, userID); err != nil {
+		return nil, err
+	}
+	codes, err := s.newRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Log.Info("MFA enabled", "user_id", userID)
+	return codes, nil
+}
+
+// Disable turns MFA off after checking a current code or a recovery code.
+// Users of privileged roles cannot turn it off while it is mandatory for them.
+func (s *MFAService) Disable(ctx context.Context, userID int, code string) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if !user.MFAEnabled() {
+		return errors2.ErrMFANotEnabled
+	}
+	mandatory, err := s.mandatoryFor(ctx, user)
+	if err != nil {
+		return err
+	}
+	if mandatory {
+		logger.Log.Warn("Disable MFA forbidden: mandatory for role", "user_id", userID, "role", user.Role)
+		return errors2.ErrForbidden
+	}
+
+	ok, err := s.verify(ctx, user, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors2.ErrInvalidMFACode
+	}
+
+	if err := s.userRepo.DisableMFA(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.recoveryRepo.DeleteByUser(ctx, userID); err != nil {
+		return err
+	}
+
+	logger.Log.Info("MFA disabled", "user_id", userID)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes of the user.
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.MFAEnabled() {
+		return nil, errors2.ErrMFANotEnabled
+	}
+
+	ok, err := s.verify(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors2.ErrInvalidMFACode
+	}
+
+	return s.newRecoveryCodes(ctx, userID)
+}
+
+// ChallengePurpose tells whether a login of the user needs a second step:
+// MFAPurposeLogin for enrolled users, MFAPurposeEnroll for users who must
+// enrol first, or "" if the password is enough.
+func (s *MFAService) ChallengePurpose(ctx context.Context, user *models.User) (string, error) {
+	if user.MFAEnabled() {
+		return models.MFAPurposeLogin, nil
+	}
+	mandatory, err := s.mandatoryFor(ctx, user)
+	if err != nil || !mandatory {
+		return "", err
+	}
+	return models.MFAPurposeEnroll, nil
+}
+
+// StartChallenge creates a short-lived challenge and returns its token.
+func (s *MFAService) StartChallenge(ctx context.Context, userID int, purpose string) (string, error) {
+	plain, hash, err := token.NewSecret()
+	if err != nil {
+		return "", err
+	}
+	challenge := models.MFAChallenge{UserID: userID, Purpose: purpose}
+	if err := s.challengeRepo.Create(ctx, hash, challenge, mfaChallengeTTL); err != nil {
+		return "", err
+	}
+	return plain, nil
+}
+
+// LoadChallenge returns the challenge of the token or ErrInvalidToken.
+func (s *MFAService) LoadChallenge(ctx context.Context, mfaToken string) (*models.MFAChallenge, error) {
+	challenge, err := s.challengeRepo.Get(ctx, token.HashSecret(mfaToken))
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil {
+		return nil, errors2.ErrInvalidToken
+	}
+	return challenge, nil
+}
+
+// FailChallenge counts a wrong code and drops the challenge after too many.
+func (s *MFAService) FailChallenge(ctx context.Context, mfaToken string) error {
+	hash := token.HashSecret(mfaToken)
+	attempts, err := s.challengeRepo.AddAttempt(ctx, hash)
+	if err != nil {
+		return err
+	}
+	if attempts >= mfaMaxAttempts {
+		logger.Log.Warn("MFA challenge dropped after too many attempts")
+		return s.challengeRepo.Delete(ctx, hash)
+	}
+	return nil
+}
+
+func (s *MFAService) EndChallenge(ctx context.Context, mfaToken string) error {
+	return s.challengeRepo.Delete(ctx, token.HashSecret(mfaToken))
+}
+
+// VerifyLogin checks the second factor of an enrolled user during login.
+func (s *MFAService) VerifyLogin(ctx context.Context, user *models.User, code string) (bool, error) {
+	if !user.MFAEnabled() {
+		return false, nil
+	}
+	return s.verify(ctx, user, code)
+}
+
+// verify accepts a TOTP code that has not been used yet or, once MFA is
+// enabled, an unused recovery code.
+func (s *MFAService) verify(ctx context.Context, user *models.User, code string) (bool, error) {
+	if step, ok := totp.Validate(user.MFASecret, code, time.Now(), totpSkew); ok {
+		fresh, err := s.challengeRepo.ClaimStep(ctx, user.ID, step, totpStepTTL)
+		if err != nil {
+			return false, err
+		}
+		if !fresh {
+			logger.Log.Warn("TOTP code replayed", "user_id", user.ID)
+		}
+		return fresh, nil
+	}
+
+	if !user.MFAEnabled() {
+		return false, nil
+	}
+	used, err := s.recoveryRepo.Consume(ctx, user.ID, token.HashSecret(normalizeRecoveryCode(code)))
+	if err != nil {
+		return false, err
+	}
+	if used {
+		logger.Log.Info("Recovery code used", "user_id", user.ID)
+	}
+	return used, nil
+}
+
+func (s *MFAService) newRecoveryCodes(ctx context.Context, userID int) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, recoveryCodeLength)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))[:recoveryCodeLength]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, token.HashSecret(raw))
+	}
+
+	if err := s.recoveryRepo.Replace(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// mandatoryFor reports whether MFA is required for the user: with
+// requiredForAdmin set, for every role that can manage other users.
+func (s *MFAService) mandatoryFor(ctx context.Context, user *models.User) (bool, error) {
+	if !s.requiredForAdmin {
+		return false, nil
+	}
+	return s.authz.Privileged(ctx, user.Role)
+}
+
+func (s *MFAService) getUser(userID int) (*models.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors2.ErrNotFound
+	}
+	return user, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+}
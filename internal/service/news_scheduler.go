@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	"news-api/pkg/logger"
+	"strconv"
+	"time"
+)
+
+// NewsScheduler publishes scheduled news when its publish_at comes and
+// archives published news when its unpublish_at passes. Every replica runs
+// it; the repository lets only one of them apply the changes at a time.
+type NewsScheduler struct {
+	repo  interfaces.NewsRepository
+	audit *AuditLog
+}
+
+func NewNewsScheduler(repo interfaces.NewsRepository, audit *AuditLog) *NewsScheduler {
+	return &NewsScheduler{repo: repo, audit: audit}
+}
+
+// Run applies due changes every interval until ctx is cancelled.
+func (s *NewsScheduler) Run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.ApplyDue(ctx); err != nil {
+				logger.Log.Error("Scheduled news update failed", "error", err)
+			}
+		}
+	}
+}
+
+func (s *NewsScheduler) ApplyDue(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	published, archived, err := s.repo.ApplySchedule(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range published {
+		logger.Log.Info("Scheduled news published", "news_id", id)
+		s.audit.Success(ctx, models.Actor{}, models.AuditNewsStatus, models.AuditTargetNews, strconv.Itoa(id),
+			models.NewsScheduled+" -> "+models.NewsPublished+" by schedule")
+	}
+	for _, id := range archived {
+		logger.Log.Info("Expired news archived", "news_id", id)
+		s.audit.Success(ctx, models.Actor{}, models.AuditNewsStatus, models.AuditTargetNews, strconv.Itoa(id),
+			models.NewsPublished+" -> "+models.NewsArchived+" by schedule")
+	}
+	return nil
+}
@@ -3,51 +3,109 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	errors2 "news-api/internal/dto/errors"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"news-api/internal/models"
 	"news-api/internal/repository/interfaces"
 	"news-api/pkg/logger"
+	"news-api/pkg/textdiff"
 )
 
 type NewsService struct {
-	repo interfaces.NewsRepository
+	repo     interfaces.NewsRepository
+	taxonomy interfaces.TaxonomyRepository
+	authz    *Authorizer
+	audit    *AuditLog
 }
 
-func NewNewsService(repo interfaces.NewsRepository) *NewsService {
-	return &NewsService{repo: repo}
+func NewNewsService(
+	repo interfaces.NewsRepository,
+	taxonomy interfaces.TaxonomyRepository,
+	authz *Authorizer,
+	audit *AuditLog,
+) *NewsService {
+	return &NewsService{repo: repo, taxonomy: taxonomy, authz: authz, audit: audit}
 }
 
 const (
 	maxTitleLen = 255
-	adminRole   = "admin"
-	editorRole  = "editor"
 )
 
+// Who may move a news item between statuses: whoever may edit the item, the
+// holders of news:publish, or either of them.
+const (
+	byEditor = 1 << iota
+	byPublisher
+)
+
+// newsTransitions lists the allowed status changes. Authors submit drafts
+// for review and may take them back; publishers publish, schedule or reject
+// them and archive what is no longer current. Scheduled news is published by
+// NewsScheduler, or earlier by hand.
+var newsTransitions = map[string]map[string]int{
+	models.NewsDraft: {models.NewsInReview: byEditor},
+	models.NewsInReview: {
+		models.NewsDraft:     byEditor | byPublisher,
+		models.NewsScheduled: byPublisher,
+		models.NewsPublished: byPublisher,
+	},
+	models.NewsScheduled: {
+		models.NewsInReview:  byEditor | byPublisher,
+		models.NewsScheduled: byPublisher,
+		models.NewsPublished: byPublisher,
+	},
+	models.NewsPublished: {models.NewsArchived: byPublisher},
+}
+
 func (s *NewsService) CreateNews(ctx context.Context, actor models.Actor, n *models.News) error {
 	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
 	defer cancel()
 
-	if actor.Role != adminRole && actor.Role != editorRole {
-		logger.Log.Warn("Create news forbidden: role mismatch", "role", actor.Role)
-		return errors2.ErrForbidden
+	if err := s.authz.Require(ctx, actor, models.PermNewsCreate); err != nil {
+		s.auditDenied(ctx, actor, models.AuditNewsCreate, "", err)
+		return err
+	}
+	if err := requireVerifiedEmail(actor); err != nil {
+		logger.Log.Warn("Create news forbidden: email not verified", "user_id", actor.UserID)
+		return err
+	}
+	if err := requireScope(actor, models.ScopeNewsCreate); err != nil {
+		logger.Log.Warn("Create news forbidden: API key scope", "user_id", actor.UserID, "key_id", actor.APIKeyID)
+		return err
 	}
 
 	if err := validateNewsPayload(n); err != nil {
 		logger.Log.Warn("Create news validation failed", "error", err)
 		return err
 	}
+	if err := normalizeNewsTaxonomy(ctx, s.taxonomy, n); err != nil {
+		logger.Log.Warn("Create news validation failed", "error", err)
+		return err
+	}
+	if n.CategoryIDs == nil {
+		n.CategoryIDs = []int{}
+	}
+	if n.Tags == nil {
+		n.Tags = []string{}
+	}
 
-	n.AuthorID = actor.UserID
+	authorID := actor.UserID
+	n.AuthorID = &authorID
+	n.Status = models.NewsDraft
 
 	if err := s.repo.Create(n); err != nil {
-		logger.Log.Error("Create news failed", "error", err, "author_id", n.AuthorID)
+		logger.Log.Error("Create news failed", "error", err, "author_id", authorID)
 		return err
 	}
 
-	logger.Log.Info("News created", "news_id", n.ID, "author_id", n.AuthorID)
+	logger.Log.Info("News created", "news_id", n.ID, "author_id", authorID)
+	s.audit.Success(ctx, actor, models.AuditNewsCreate, models.AuditTargetNews, strconv.Itoa(n.ID), "")
 	return nil
 }
 
@@ -55,18 +113,58 @@ func (s *NewsService) UpdateNews(ctx context.Context, actor models.Actor, n *mod
 	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
 	defer cancel()
 
-	if actor.Role != adminRole && actor.Role != editorRole {
-		logger.Log.Warn("Update news forbidden: role mismatch", "role", actor.Role)
-		return errors2.ErrForbidden
-	}
 	if n.ID <= 0 {
 		return errors.Join(errors2.ErrValidation, errors.New("id is required"))
 	}
-
 	if err := validateNewsPayload(n); err != nil {
 		logger.Log.Warn("Update news validation failed", "error", err, "news_id", n.ID)
 		return err
 	}
+	if err := normalizeNewsTaxonomy(ctx, s.taxonomy, n); err != nil {
+		logger.Log.Warn("Update news validation failed", "error", err, "news_id", n.ID)
+		return err
+	}
+	return s.update(ctx, actor, n, nil)
+}
+
+// RestoreRevision brings back the text of an earlier revision. The text is
+// saved as a new revision, so the restore itself can be undone the same way.
+func (s *NewsService) RestoreRevision(ctx context.Context, actor models.Actor, id, revision int) (*models.News, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if id <= 0 || revision <= 0 {
+		return nil, errors.Join(errors2.ErrValidation, errors.New("id and revision are required"))
+	}
+
+	rev, err := s.repo.GetRevision(id, revision)
+	if err != nil {
+		logger.Log.Error("GetRevision before restore failed", "error", err, "news_id", id, "revision", revision)
+		return nil, err
+	}
+	if rev == nil {
+		return nil, errors2.ErrNotFound
+	}
+
+	n := &models.News{ID: id, Title: rev.Title, Description: rev.Description}
+	if err := s.update(ctx, actor, n, &revision); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// update saves new text of the news as the next revision after checking that
+// the actor may edit it. restoredFrom is the revision the text was taken
+// from, if any.
+func (s *NewsService) update(ctx context.Context, actor models.Actor, n *models.News, restoredFrom *int) error {
+	if err := requireVerifiedEmail(actor); err != nil {
+		logger.Log.Warn("Update news forbidden: email not verified", "user_id", actor.UserID)
+		return err
+	}
+	if err := requireScope(actor, models.ScopeNewsUpdate); err != nil {
+		logger.Log.Warn("Update news forbidden: API key scope", "user_id", actor.UserID, "key_id", actor.APIKeyID)
+		return err
+	}
 
 	existing, err := s.repo.GetByID(n.ID)
 	if err != nil {
@@ -78,27 +176,270 @@ func (s *NewsService) UpdateNews(ctx context.Context, actor models.Actor, n *mod
 		return errors2.ErrNotFound
 	}
 
-	if existing.AuthorID != actor.UserID {
-		logger.Log.Warn("Update news forbidden: not an author")
-		return errors2.ErrForbidden
+	if err := s.requireOwnOrAny(ctx, actor, existing, models.PermNewsUpdateOwn, models.PermNewsUpdateAny); err != nil {
+		s.auditDenied(ctx, actor, models.AuditNewsUpdate, strconv.Itoa(n.ID), err)
+		return err
+	}
+	// Published text changes only with the say of a publisher, or the review
+	// step could be skipped by editing after publication.
+	switch existing.Status {
+	case models.NewsArchived:
+		return errors.Join(errors2.ErrValidation, errors.New("archived news cannot be changed"))
+	case models.NewsScheduled, models.NewsPublished:
+		if err := s.authz.Require(ctx, actor, models.PermNewsPublish); err != nil {
+			s.auditDenied(ctx, actor, models.AuditNewsUpdate, strconv.Itoa(n.ID), err)
+			return err
+		}
 	}
 
-	if err := s.repo.Update(n); err != nil {
+	editorID := actor.UserID
+	rev := &models.NewsRevision{EditorID: &editorID, RestoredFrom: restoredFrom}
+	if err := s.repo.Update(n, rev); err != nil {
 		logger.Log.Error("Update news failed", "error", err, "news_id", n.ID)
 		return err
 	}
 
-	logger.Log.Info("News updated", "news_id", n.ID)
+	n.AuthorID = existing.AuthorID
+	n.Status = existing.Status
+	n.PublishedAt = existing.PublishedAt
+	n.PublishAt = existing.PublishAt
+	n.UnpublishAt = existing.UnpublishAt
+	n.CreatedAt = existing.CreatedAt
+	if n.CategoryIDs == nil {
+		n.CategoryIDs = existing.CategoryIDs
+	}
+	if n.Tags == nil {
+		n.Tags = existing.Tags
+	}
+
+	detail := "revision " + strconv.Itoa(rev.Revision)
+	if restoredFrom != nil {
+		detail += ", restored from revision " + strconv.Itoa(*restoredFrom)
+	}
+	logger.Log.Info("News updated", "news_id", n.ID, "revision", rev.Revision, "restored_from", restoredFrom)
+	s.audit.Success(ctx, actor, models.AuditNewsUpdate, models.AuditTargetNews, strconv.Itoa(n.ID), detail)
 	return nil
 }
 
+// ListRevisions returns the history of the news, newest first, to those who
+// may edit or review it.
+func (s *NewsService) ListRevisions(ctx context.Context, actor models.Actor, id int) ([]models.NewsRevision, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.requireHistory(ctx, actor, id); err != nil {
+		return nil, err
+	}
+	revisions, err := s.repo.ListRevisions(id)
+	if err != nil {
+		logger.Log.Error("List news revisions failed", "error", err, "news_id", id)
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// DiffRevisions compares two revisions of the news. to defaults to the
+// latest revision and from to the one before to.
+func (s *NewsService) DiffRevisions(ctx context.Context, actor models.Actor, id, from, to int) (*models.NewsRevisionDiff, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if from < 0 || to < 0 {
+		return nil, errors.Join(errors2.ErrValidation, errors.New("revisions must be positive"))
+	}
+	if err := s.requireHistory(ctx, actor, id); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.repo.ListRevisions(id)
+	if err != nil {
+		logger.Log.Error("List news revisions failed", "error", err, "news_id", id)
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, errors2.ErrNotFound
+	}
+	if to == 0 {
+		to = revisions[0].Revision
+	}
+	if from == 0 {
+		if to == 1 {
+			return nil, errors.Join(errors2.ErrValidation, errors.New("revision 1 is the first one, nothing comes before it"))
+		}
+		from = to - 1
+	}
+
+	var a, b *models.NewsRevision
+	for i := range revisions {
+		switch revisions[i].Revision {
+		case from:
+			a = &revisions[i]
+		case to:
+			b = &revisions[i]
+		}
+	}
+	if from == to {
+		a = b
+	}
+	if a == nil || b == nil {
+		return nil, errors2.ErrNotFound
+	}
+
+	return &models.NewsRevisionDiff{
+		NewsID:      id,
+		From:        from,
+		To:          to,
+		Title:       textdiff.Lines(a.Title, b.Title),
+		Description: textdiff.Lines(a.Description, b.Description),
+	}, nil
+}
+
+// requireHistory lets reviewers and those who may edit the news read its
+// history. News the actor cannot see is not found.
+func (s *NewsService) requireHistory(ctx context.Context, actor models.Actor, id int) error {
+	if id <= 0 {
+		return errors.Join(errors2.ErrValidation, errors.New("id is required"))
+	}
+	n, err := s.repo.GetByID(id)
+	if err != nil {
+		logger.Log.Error("GetByID before revisions failed", "error", err, "news_id", id)
+		return err
+	}
+	if n == nil || !s.canSee(ctx, actor, n) {
+		return errors2.ErrNotFound
+	}
+
+	reviewer, err := s.isReviewer(ctx, actor)
+	if err != nil || reviewer {
+		return err
+	}
+	return s.requireOwnOrAny(ctx, actor, n, models.PermNewsUpdateOwn, models.PermNewsUpdateAny)
+}
+
+// ChangeStatus moves the news to another editorial status if newsTransitions
+// allows it and the actor is among those who may make the change. The
+// schedule of the news is replaced by the one in change.
+func (s *NewsService) ChangeStatus(ctx context.Context, actor models.Actor, id int, change models.NewsStatusChange) (*models.News, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := requireVerifiedEmail(actor); err != nil {
+		return nil, err
+	}
+	if err := requireScope(actor, models.ScopeNewsUpdate); err != nil {
+		return nil, err
+	}
+	if id <= 0 {
+		return nil, errors.Join(errors2.ErrValidation, errors.New("id is required"))
+	}
+	status := change.Status
+	if !slices.Contains(models.NewsStatuses, status) {
+		return nil, errors.Join(errors2.ErrValidation, fmt.Errorf("unknown status %q", status))
+	}
+	if err := validateSchedule(change, time.Now()); err != nil {
+		return nil, err
+	}
+
+	n, err := s.repo.GetByID(id)
+	if err != nil {
+		logger.Log.Error("GetByID before status change failed", "error", err, "news_id", id)
+		return nil, err
+	}
+	if n == nil || !s.canSee(ctx, actor, n) {
+		return nil, errors2.ErrNotFound
+	}
+
+	guard, ok := newsTransitions[n.Status][status]
+	if !ok {
+		return nil, errors.Join(errors2.ErrValidation, fmt.Errorf("news cannot go from %s to %s", n.Status, status))
+	}
+	if err := s.requireTransition(ctx, actor, n, guard); err != nil {
+		s.auditDenied(ctx, actor, models.AuditNewsStatus, strconv.Itoa(id), err)
+		return nil, err
+	}
+
+	from := n.Status
+	n.Status = status
+	n.PublishAt = change.PublishAt
+	n.UnpublishAt = change.UnpublishAt
+	changed, err := s.repo.UpdateStatus(n, from)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return nil, errors.Join(errors2.ErrValidation, errors.New("the status was changed by someone else, reload and try again"))
+	}
+
+	detail := from + " -> " + status
+	if n.PublishAt != nil {
+		detail += ", publish at " + n.PublishAt.UTC().Format(time.RFC3339)
+	}
+	if n.UnpublishAt != nil {
+		detail += ", unpublish at " + n.UnpublishAt.UTC().Format(time.RFC3339)
+	}
+	logger.Log.Info("News status changed", "news_id", id, "from", from, "to", status, "user_id", actor.UserID,
+		"publish_at", n.PublishAt, "unpublish_at", n.UnpublishAt)
+	s.audit.Success(ctx, actor, models.AuditNewsStatus, models.AuditTargetNews, strconv.Itoa(id), detail)
+	return n, nil
+}
+
+// validateSchedule checks the times of a status change: scheduling needs a
+// future publish_at, and only scheduled or published news can have an
+// unpublish_at, which must come after publication.
+func validateSchedule(change models.NewsStatusChange, now time.Time) error {
+	switch change.Status {
+	case models.NewsScheduled:
+		if change.PublishAt == nil {
+			return errors.Join(errors2.ErrValidation, errors.New("publish_at is required for scheduled news"))
+		}
+		if !change.PublishAt.After(now) {
+			return errors.Join(errors2.ErrValidation, errors.New("publish_at must be in the future"))
+		}
+	case models.NewsPublished:
+	default:
+		if change.UnpublishAt != nil {
+			return errors.Join(errors2.ErrValidation, fmt.Errorf("unpublish_at cannot be set for %s news", change.Status))
+		}
+	}
+	if change.PublishAt != nil && change.Status != models.NewsScheduled {
+		return errors.Join(errors2.ErrValidation, errors.New("publish_at is only for scheduled news"))
+	}
+	if change.UnpublishAt != nil {
+		publishAt := now
+		if change.PublishAt != nil {
+			publishAt = *change.PublishAt
+		}
+		if !change.UnpublishAt.After(publishAt) {
+			return errors.Join(errors2.ErrValidation, errors.New("unpublish_at must be after the news is published"))
+		}
+	}
+	return nil
+}
+
+func (s *NewsService) requireTransition(ctx context.Context, actor models.Actor, n *models.News, guard int) error {
+	if guard&byPublisher != 0 {
+		ok, err := s.authz.Can(ctx, actor, models.PermNewsPublish)
+		if err != nil || ok {
+			return err
+		}
+		if guard&byEditor == 0 {
+			return s.authz.Require(ctx, actor, models.PermNewsPublish)
+		}
+	}
+	return s.requireOwnOrAny(ctx, actor, n, models.PermNewsUpdateOwn, models.PermNewsUpdateAny)
+}
+
 func (s *NewsService) DeleteNews(ctx context.Context, actor models.Actor, id int) error {
 	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
 	defer cancel()
 
-	if actor.Role != adminRole {
-		logger.Log.Warn("Delete news forbidden: non-admin", "role", actor.Role)
-		return errors2.ErrForbidden
+	if err := requireVerifiedEmail(actor); err != nil {
+		logger.Log.Warn("Delete news forbidden: email not verified", "user_id", actor.UserID)
+		return err
+	}
+	if err := requireScope(actor, models.ScopeNewsDelete); err != nil {
+		logger.Log.Warn("Delete news forbidden: API key scope", "user_id", actor.UserID, "key_id", actor.APIKeyID)
+		return err
 	}
 	if id <= 0 {
 		return errors.Join(errors2.ErrValidation, errors.New("id is required"))
@@ -113,17 +454,70 @@ func (s *NewsService) DeleteNews(ctx context.Context, actor models.Actor, id int
 		logger.Log.Warn("News not found for delete", "news_id", id)
 		return errors2.ErrNotFound
 	}
+	if err := s.requireOwnOrAny(ctx, actor, existing, models.PermNewsDeleteOwn, models.PermNewsDeleteAny); err != nil {
+		s.auditDenied(ctx, actor, models.AuditNewsDelete, strconv.Itoa(id), err)
+		return err
+	}
 
-	if err := s.repo.Delete(id); err != nil {
+	if err := s.repo.Delete(id, actor.UserID); err != nil {
 		logger.Log.Error("Delete news failed", "error", err, "news_id", id)
 		return err
 	}
 
-	logger.Log.Info("News deleted", "news_id", id)
+	logger.Log.Info("News moved to trash", "news_id", id, "user_id", actor.UserID)
+	s.audit.Success(ctx, actor, models.AuditNewsDelete, models.AuditTargetNews, strconv.Itoa(id), "moved to trash")
 	return nil
 }
 
-func (s *NewsService) GetByIDNews(ctx context.Context, id int) (*models.News, error) {
+// ListTrash lists deleted news that has not been purged yet.
+func (s *NewsService) ListTrash(ctx context.Context, actor models.Actor, p models.NewsListParams) ([]models.News, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermNewsTrash); err != nil {
+		return nil, 0, err
+	}
+	p.Normalize()
+	list, total, err := s.repo.ListDeleted(p)
+	if err != nil {
+		logger.Log.Error("List trash failed", "error", err, "limit", p.Limit, "offset", p.Offset)
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// RestoreNews takes the news out of the trash with the status and schedule
+// it had when it was deleted.
+func (s *NewsService) RestoreNews(ctx context.Context, actor models.Actor, id int) (*models.News, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if id <= 0 {
+		return nil, errors.Join(errors2.ErrValidation, errors.New("id is required"))
+	}
+	if err := s.authz.Require(ctx, actor, models.PermNewsTrash); err != nil {
+		s.auditDenied(ctx, actor, models.AuditNewsRestore, strconv.Itoa(id), err)
+		return nil, err
+	}
+
+	n, err := s.repo.Restore(id)
+	if err != nil {
+		logger.Log.Error("Restore news failed", "error", err, "news_id", id)
+		return nil, err
+	}
+	if n == nil {
+		return nil, errors2.ErrNotFound
+	}
+
+	logger.Log.Info("News restored from trash", "news_id", id, "user_id", actor.UserID)
+	s.audit.Success(ctx, actor, models.AuditNewsRestore, models.AuditTargetNews, strconv.Itoa(id), "")
+	return n, nil
+}
+
+// GetByIDNews returns published news to everyone. Unpublished news is shown
+// only to its author and to those who may edit or publish it, and is not
+// found for anyone else.
+func (s *NewsService) GetByIDNews(ctx context.Context, actor models.Actor, id int) (*models.News, error) {
 	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
 	defer cancel()
 
@@ -136,16 +530,49 @@ func (s *NewsService) GetByIDNews(ctx context.Context, id int) (*models.News, er
 		logger.Log.Error("GetByID failed", "error", err, "news_id", id)
 		return nil, err
 	}
-	if n == nil {
+	if n == nil || !s.canSee(ctx, actor, n) {
 		return nil, errors2.ErrNotFound
 	}
 	return n, nil
 }
 
-func (s *NewsService) ListNews(ctx context.Context, p models.NewsListParams) ([]models.News, error) {
+// ListNews lists published news unless p.Status asks for another status.
+// Unpublished news is listed in full for reviewers; other users get only
+// their own, and anonymous callers none.
+func (s *NewsService) ListNews(ctx context.Context, actor models.Actor, p models.NewsListParams) ([]models.News, error) {
 	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
 	defer cancel()
 
+	if p.Status == nil {
+		published := models.NewsPublished
+		p.Status = &published
+	}
+	if p.Tag != nil {
+		tag, err := normalizeTag(*p.Tag)
+		if err != nil {
+			return nil, err
+		}
+		p.Tag = &tag
+	}
+	if *p.Status != models.NewsPublished {
+		if !slices.Contains(models.NewsStatuses, *p.Status) {
+			return nil, errors.Join(errors2.ErrValidation, fmt.Errorf("unknown status %q", *p.Status))
+		}
+		if actor.UserID == 0 {
+			return nil, errors2.ErrUnauthorized
+		}
+		reviewer, err := s.isReviewer(ctx, actor)
+		if err != nil {
+			return nil, err
+		}
+		if !reviewer {
+			if p.AuthorID != nil && *p.AuthorID != actor.UserID {
+				return nil, errors.Join(errors2.ErrForbidden, errors.New("only your own unpublished news can be listed"))
+			}
+			p.AuthorID = &actor.UserID
+		}
+	}
+
 	p.Normalize()
 	list, err := s.repo.List(p)
 	if err != nil {
@@ -156,6 +583,62 @@ func (s *NewsService) ListNews(ctx context.Context, p models.NewsListParams) ([]
 	return list, nil
 }
 
+// canSee reports whether the actor may read the news in its current status.
+func (s *NewsService) canSee(ctx context.Context, actor models.Actor, n *models.News) bool {
+	if n.Public(time.Now()) || (actor.UserID != 0 && n.WrittenBy(actor.UserID)) {
+		return true
+	}
+	if actor.UserID == 0 {
+		return false
+	}
+	ok, err := s.isReviewer(ctx, actor)
+	if err != nil {
+		logger.Log.Error("Permission check failed", "error", err, "news_id", n.ID)
+	}
+	return ok
+}
+
+// isReviewer reports whether the actor works on news of other authors and so
+// sees them before publication.
+func (s *NewsService) isReviewer(ctx context.Context, actor models.Actor) (bool, error) {
+	for _, perm := range []string{models.PermNewsPublish, models.PermNewsUpdateAny} {
+		ok, err := s.authz.Can(ctx, actor, perm)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// requireOwnOrAny checks the "own" permission for the author of the news and
+// the "any" permission for everyone else. Holding "any" covers own news too.
+func (s *NewsService) requireOwnOrAny(ctx context.Context, actor models.Actor, n *models.News, own, anyAuthor string) error {
+	if n.WrittenBy(actor.UserID) {
+		ok, err := s.authz.Can(ctx, actor, own)
+		if err != nil || ok {
+			return err
+		}
+	}
+	return s.authz.Require(ctx, actor, anyAuthor)
+}
+
+// auditDenied records an attempt refused for lack of permission. Other errors
+// of the check are not about the actor and are left out.
+func (s *NewsService) auditDenied(ctx context.Context, actor models.Actor, action, newsID string, err error) {
+	if errors.Is(err, errors2.ErrForbidden) {
+		s.audit.Failure(ctx, actor, action, models.AuditTargetNews, newsID, "permission denied")
+	}
+}
+
+// requireVerifiedEmail refuses content writes from accounts that have not
+// confirmed their email yet.
+func requireVerifiedEmail(actor models.Actor) error {
+	if !actor.EmailVerified {
+		return errors.Join(errors2.ErrForbidden, errors2.ErrEmailNotVerified)
+	}
+	return nil
+}
+
 func validateNewsPayload(n *models.News) error {
 	title := strings.TrimSpace(n.Title)
 	desc := strings.TrimSpace(n.Description)
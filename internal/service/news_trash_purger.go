@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	"news-api/pkg/logger"
+	"strconv"
+	"time"
+)
+
+// NewsTrashPurger removes for good the news that has stayed in the trash
+// longer than the retention period. Replicas may run it at the same time:
+// each trashed item is deleted, and audited, by only one of them.
+type NewsTrashPurger struct {
+	repo      interfaces.NewsRepository
+	audit     *AuditLog
+	retention time.Duration
+}
+
+func NewNewsTrashPurger(repo interfaces.NewsRepository, audit *AuditLog, retention time.Duration) *NewsTrashPurger {
+	return &NewsTrashPurger{repo: repo, audit: audit, retention: retention}
+}
+
+// Run purges expired news every interval until ctx is cancelled.
+func (p *NewsTrashPurger) Run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.PurgeExpired(ctx); err != nil {
+				logger.Log.Error("Trash purge failed", "error", err)
+			}
+		}
+	}
+}
+
+func (p *NewsTrashPurger) PurgeExpired(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	ids, err := p.repo.PurgeDeleted(ctx, time.Now().Add(-p.retention))
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		logger.Log.Info("News purged from trash", "news_id", id)
+		p.audit.Success(ctx, models.Actor{}, models.AuditNewsPurge, models.AuditTargetNews, strconv.Itoa(id),
+			"in the trash longer than "+strconv.Itoa(int(p.retention.Hours()/24))+" days")
+	}
+	return nil
+}
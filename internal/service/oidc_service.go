@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	"news-api/pkg/logger"
+	"news-api/pkg/oidc"
+	"news-api/pkg/password"
+	"news-api/pkg/token"
+	"strings"
+	"time"
+)
+
+const oidcStateTTL = 10 * time.Minute
+
+// OIDCRoleMapping maps identity provider groups to our roles. With both lists
+// empty every SSO user is an editor and local roles are left alone; otherwise
+// the provider decides between admin and editor on each login and users in
+// none of the groups are refused. Custom roles given by admins are kept.
+type OIDCRoleMapping struct {
+	AdminGroups  []string
+	EditorGroups []string
+}
+
+type OIDCService struct {
+	provider     *oidc.Provider
+	issuer       string
+	userRepo     interfaces.UserRepository
+	identityRepo interfaces.UserIdentityRepository
+	stateRepo    interfaces.OIDCStateRepository
+	authService  *AuthService
+	verifier     *VerificationService
+	roles        OIDCRoleMapping
+}
+
+func NewOIDCService(
+	provider *oidc.Provider,
+	issuer string,
+	userRepo interfaces.UserRepository,
+	identityRepo interfaces.UserIdentityRepository,
+	stateRepo interfaces.OIDCStateRepository,
+	authService *AuthService,
+	verifier *VerificationService,
+	roles OIDCRoleMapping,
+) *OIDCService {
+	return &OIDCService{
+		provider:     provider,
+		issuer:       issuer,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		stateRepo:    stateRepo,
+		authService:  authService,
+		verifier:     verifier,
+		roles:        roles,
+	}
+}
+
+// AuthURL starts an authorization code login with PKCE and returns the
+// identity provider URL to redirect the browser to.
+func (s *OIDCService) AuthURL(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	state, err := oidc.NewNonce()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := oidc.NewNonce()
+	if err != nil {
+		return "", err
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.stateRepo.Save(ctx, token.HashSecret(state), models.OIDCState{CodeVerifier: verifier, Nonce: nonce}, oidcStateTTL); err != nil {
+		return "", err
+	}
+
+	authURL, err := s.provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		logger.Log.Error("OIDC provider unavailable", "error", err)
+		return "", err
+	}
+	return authURL, nil
+}
+
+// Callback finishes the login started by AuthURL: it redeems the code,
+// verifies the ID token, finds or provisions the user and logs them in.
+func (s *OIDCService) Callback(ctx context.Context, code, state string, client models.ClientInfo) (*models.LoginResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*contextTimeout)
+	defer cancel()
+
+	saved, err := s.stateRepo.Consume(ctx, token.HashSecret(state))
+	if err != nil {
+		return nil, err
+	}
+	if saved == nil {
+		logger.Log.Warn("OIDC callback with unknown state")
+		return nil, errors2.ErrInvalidToken
+	}
+
+	rawIDToken, err := s.provider.Exchange(ctx, code, saved.CodeVerifier)
+	if err != nil {
+		logger.Log.Warn("OIDC code exchange failed", "error", err)
+		return nil, errors.Join(errors2.ErrUnauthorized, err)
+	}
+	claims, err := s.provider.VerifyIDToken(ctx, rawIDToken, saved.Nonce)
+	if err != nil {
+		logger.Log.Warn("OIDC ID token rejected", "error", err)
+		return nil, errors.Join(errors2.ErrUnauthorized, err)
+	}
+
+	user, err := s.resolveUser(ctx, claims, client)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Log.Info("OIDC login", slog.Int("user_id", user.ID), slog.String("subject", claims.Subject))
+	return s.authService.completeLogin(ctx, user, client)
+}
+
+// resolveUser returns the user linked to the identity. An unlinked identity
+// is linked to the local account with the same email if both sides have
+// verified it, or gets a new account.
+func (s *OIDCService) resolveUser(ctx context.Context, claims *oidc.Claims, client models.ClientInfo) (*models.User, error) {
+	role, ok := s.roleFor(claims.Groups)
+	if !ok {
+		logger.Log.Warn("OIDC login refused: no mapped group", slog.String("subject", claims.Subject), slog.Any("groups", claims.Groups))
+		return nil, errors2.ErrForbidden
+	}
+
+	identity, err := s.identityRepo.Get(ctx, s.issuer, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if identity != nil {
+		user, err := s.userRepo.GetByID(identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, errors2.ErrUnauthorized
+		}
+		if err := s.identityRepo.TouchLogin(ctx, identity.ID, claims.Email); err != nil {
+			return nil, err
+		}
+		return user, s.syncRole(ctx, user, role, client)
+	}
+
+	if claims.Email == "" {
+		return nil, errors.Join(errors2.ErrValidation, errors.New("identity provider did not return an email"))
+	}
+
+	user, err := s.userRepo.GetByEmail(claims.Email)
+	if err != nil {
+		return nil, err
+	}
+	if user != nil {
+		// Linking on an unverified email on either side would let whoever
+		// registered the address first take over the other account.
+		if !claims.EmailVerified || !user.EmailVerified() {
+			logger.Log.Warn("OIDC login refused: email belongs to another account", slog.String("email", claims.Email))
+			return nil, errors2.ErrAccountExists
+		}
+		if err := s.link(ctx, user, claims); err != nil {
+			return nil, err
+		}
+		logger.Log.Info("OIDC identity linked", slog.Int("user_id", user.ID), slog.String("subject", claims.Subject))
+		return user, s.syncRole(ctx, user, role, client)
+	}
+
+	return s.provision(ctx, claims, role)
+}
+
+func (s *OIDCService) provision(ctx context.Context, claims *oidc.Claims, role string) (*models.User, error) {
+	// SSO users have no password of their own; a random one keeps password
+	// login closed until they set one through a reset.
+	secret, _, err := token.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := password.HashPassword(secret)
+	if err != nil {
+		logger.Log.Error("Failed to hash password: " + err.Error())
+		return nil, err
+	}
+
+	firstName, lastName := namesOf(claims)
+	user := &models.User{
+		FirstName: firstName,
+		LastName:  lastName,
+		Email:     claims.Email,
+		Password:  hashedPassword,
+		Role:      role,
+		Avatar:    claims.Picture,
+		CreatedAt: time.Now(),
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if claims.EmailVerified {
+		if err := s.userRepo.MarkEmailVerified(ctx, user.ID, user.Email); err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	} else if err := s.verifier.SendVerification(ctx, user, user.Email); err != nil {
+		logger.Log.Warn("Verification email not sent", slog.Int("user_id", user.ID), slog.String("error", err.Error()))
+	}
+
+	if err := s.link(ctx, user, claims); err != nil {
+		return nil, err
+	}
+	logger.Log.Info("User provisioned from OIDC", slog.Int("user_id", user.ID), slog.String("role", role))
+	return user, nil
+}
+
+func (s *OIDCService) link(ctx context.Context, user *models.User, claims *oidc.Claims) error {
+	return s.identityRepo.Create(ctx, &models.UserIdentity{
+		UserID:   user.ID,
+		Provider: s.issuer,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	})
+}
+
+// syncRole applies the role from the provider groups when a mapping is
+// configured, with the checks and effects of an admin changing it. Only the
+// roles the mapping hands out are replaced. A change that would leave nobody
+// able to manage roles is skipped and the user keeps the current role.
+func (s *OIDCService) syncRole(ctx context.Context, user *models.User, role string, client models.ClientInfo) error {
+	if !s.roles.configured() || user.Role == role || !s.roles.owns(user.Role) {
+		return nil
+	}
+	err := s.authService.changeRole(ctx, models.Actor{Client: client}, user, role, "OIDC groups")
+	if errors.Is(err, errors2.ErrValidation) {
+		logger.Log.Warn("OIDC role change skipped", slog.Int("user_id", user.ID), slog.String("to", role), slog.String("error", err.Error()))
+		return nil
+	}
+	return err
+}
+
+func (s *OIDCService) roleFor(groups []string) (string, bool) {
+	if !s.roles.configured() {
+		return models.RoleEditor, true
+	}
+	switch {
+	case containsAny(groups, s.roles.AdminGroups):
+		return models.RoleAdmin, true
+	case containsAny(groups, s.roles.EditorGroups):
+		return models.RoleEditor, true
+	default:
+		return "", false
+	}
+}
+
+func (m OIDCRoleMapping) configured() bool {
+	return len(m.AdminGroups) > 0 || len(m.EditorGroups) > 0
+}
+
+// owns reports whether the mapping hands out the role.
+func (m OIDCRoleMapping) owns(role string) bool {
+	return role == models.RoleAdmin || role == models.RoleEditor
+}
+
+func containsAny(have, want []string) bool {
+	for _, h := range have {
+		for _, w := range want {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func namesOf(claims *oidc.Claims) (string, string) {
+	if claims.GivenName != "" || claims.FamilyName != "" {
+		return claims.GivenName, claims.FamilyName
+	}
+	if first, last, ok := strings.Cut(strings.TrimSpace(claims.Name), " "); ok {
+		return first, strings.TrimSpace(last)
+	}
+	if claims.Name != "" {
+		return claims.Name, ""
+	}
+	local, _, _ := strings.Cut(claims.Email, "@")
+	return local, ""
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	"news-api/pkg/logger"
+	"news-api/pkg/mailer"
+	"news-api/pkg/password"
+	"news-api/pkg/token"
+	"time"
+)
+
+// passwordResetCooldown is how long after a reset link was sent no further
+// link is sent to the same account.
+const passwordResetCooldown = time.Minute
+
+type PasswordService struct {
+	userRepo    interfaces.UserRepository
+	resetRepo   interfaces.PasswordResetRepository
+	authService *AuthService
+	mailer      mailer.Mailer
+	frontendURL string
+	resetTTL    time.Duration
+}
+
+func NewPasswordService(
+	userRepo interfaces.UserRepository,
+	resetRepo interfaces.PasswordResetRepository,
+	authService *AuthService,
+	mailer mailer.Mailer,
+	frontendURL string,
+	resetTTL time.Duration,
+) *PasswordService {
+	return &PasswordService{
+		userRepo:    userRepo,
+		resetRepo:   resetRepo,
+		authService: authService,
+		mailer:      mailer,
+		frontendURL: frontendURL,
+		resetTTL:    resetTTL,
+	}
+}
+
+// ForgotPassword emails a reset link if an account with the email exists.
+// Unknown emails are not reported so that accounts cannot be enumerated, and
+// repeated requests within passwordResetCooldown are dropped. The email is
+// sent in the background, so neither its delivery time nor its failure shows
+// in the response.
+func (s *PasswordService) ForgotPassword(ctx context.Context, email string) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		logger.Log.Error("Forgot password: user lookup failed", "error", err)
+		return err
+	}
+	if user == nil {
+		logger.Log.Info("Forgot password for unknown email", slog.String("email", email))
+		return nil
+	}
+
+	plain, hash, err := token.NewSecret()
+	if err != nil {
+		logger.Log.Error("Failed to generate reset token: " + err.Error())
+		return err
+	}
+
+	resetToken := models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(s.resetTTL),
+	}
+	created, err := s.resetRepo.Create(ctx, &resetToken, passwordResetCooldown)
+	if err != nil {
+		return err
+	}
+	if !created {
+		logger.Log.Info("Password reset not sent: cooldown", "user_id", user.ID)
+		return nil
+	}
+
+	link := s.frontendURL + "/reset-password?token=" + url.QueryEscape(plain)
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Password reset",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nTo reset your password open the link below. It is valid for %d minutes and can be used once.\n\n%s\n\nIf you did not request a password reset, ignore this email.\n",
+			user.FirstName, int(s.resetTTL.Minutes()), link,
+		),
+	}
+	go s.sendResetEmail(context.WithoutCancel(ctx), user.ID, msg)
+
+	logger.Log.Info("Password reset requested", "user_id", user.ID)
+	return nil
+}
+
+func (s *PasswordService) sendResetEmail(ctx context.Context, userID int, msg mailer.Message) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		logger.Log.Error("Failed to send reset email", "error", err, "user_id", userID)
+		return
+	}
+	logger.Log.Info("Password reset email sent", "user_id", userID)
+}
+
+// ResetPassword sets a new password using a reset token. The token and every
+// other outstanding token of the user stop working, and all sessions of the
+// user are ended.
+func (s *PasswordService) ResetPassword(ctx context.Context, resetToken, newPassword string) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	// Look the token up first, so that a password the policy rejects does
+	// not use it up.
+	tokenHash := token.HashSecret(resetToken)
+	t, err := s.resetRepo.Get(ctx, tokenHash)
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		logger.Log.Warn("Password reset with invalid or expired token")
+		return errors2.ErrInvalidToken
+	}
+	user, err := s.userRepo.GetByID(t.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors2.ErrInvalidToken
+	}
+	if err := s.authService.checkNewPassword(newPassword, user.Email, user.FirstName, user.LastName); err != nil {
+		return err
+	}
+
+	if t, err = s.resetRepo.Consume(ctx, tokenHash); err != nil {
+		return err
+	}
+	if t == nil {
+		logger.Log.Warn("Password reset token used concurrently")
+		return errors2.ErrInvalidToken
+	}
+
+	hashed, err := password.HashPassword(newPassword)
+	if err != nil {
+		logger.Log.Error("Failed to hash password: " + err.Error())
+		return err
+	}
+	if err := s.userRepo.UpdatePassword(ctx, t.UserID, hashed); err != nil {
+		return err
+	}
+	if err := s.resetRepo.InvalidateByUser(ctx, t.UserID); err != nil {
+		return err
+	}
+	if err := s.authService.RevokeAllUserTokens(ctx, t.UserID); err != nil {
+		return err
+	}
+
+	logger.Log.Info("Password reset completed", "user_id", t.UserID)
+	return nil
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/dto/user"
+	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	"news-api/pkg/logger"
+	"news-api/pkg/password"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxNameLength = 50
+
+// ProfileService lets users view and edit their own account.
+type ProfileService struct {
+	userRepo      interfaces.UserRepository
+	newsRepo      interfaces.NewsRepository
+	verifier      *VerificationService
+	guard         *LoginGuard
+	authService   *AuthService
+	authz         *Authorizer
+	deletionGrace time.Duration
+}
+
+func NewProfileService(
+	userRepo interfaces.UserRepository,
+	newsRepo interfaces.NewsRepository,
+	verifier *VerificationService,
+	guard *LoginGuard,
+	authService *AuthService,
+	authz *Authorizer,
+	deletionGrace time.Duration,
+) *ProfileService {
+	return &ProfileService{
+		userRepo:      userRepo,
+		newsRepo:      newsRepo,
+		verifier:      verifier,
+		guard:         guard,
+		authService:   authService,
+		authz:         authz,
+		deletionGrace: deletionGrace,
+	}
+}
+
+func (s *ProfileService) GetProfile(ctx context.Context, userID int) (*models.User, error) {
+	u, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, errors2.ErrNotFound
+	}
+	return u, nil
+}
+
+func (s *ProfileService) UpdateProfile(ctx context.Context, userID int, input user.UpdateProfileRequest) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	u, err := s.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.FirstName != nil {
+		if u.FirstName, err = validateName("first_name", *input.FirstName); err != nil {
+			return nil, err
+		}
+	}
+	if input.LastName != nil {
+		if u.LastName, err = validateName("last_name", *input.LastName); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.userRepo.UpdateProfile(ctx, u.ID, u.FirstName, u.LastName); err != nil {
+		return nil, err
+	}
+
+	logger.Log.Info("Profile updated", "user_id", u.ID)
+	return u, nil
+}
+
+// ChangePassword replaces the password after checking the current one. Every
+// other session of the user is ended; the one making the request stays.
+func (s *ProfileService) ChangePassword(ctx context.Context, actor models.Actor, input user.ChangePasswordRequest, client models.ClientInfo) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	u, err := s.checkPassword(ctx, actor.UserID, input.CurrentPassword, client)
+	if err != nil {
+		return err
+	}
+	if err := s.authService.checkNewPassword(input.NewPassword, u.Email, u.FirstName, u.LastName); err != nil {
+		return err
+	}
+
+	hashed, err := password.HashPassword(input.NewPassword)
+	if err != nil {
+		logger.Log.Error("Failed to hash password: " + err.Error())
+		return err
+	}
+	if err := s.userRepo.UpdatePassword(ctx, u.ID, hashed); err != nil {
+		return err
+	}
+	if err := s.authService.RevokeOtherSessions(ctx, u.ID, actor.SessionID); err != nil {
+		return err
+	}
+
+	logger.Log.Info("Password changed", "user_id", u.ID)
+	return nil
+}
+
+// RequestEmailChange sends a confirmation link to the new address. The
+// account keeps its current email until the link is opened.
+func (s *ProfileService) RequestEmailChange(ctx context.Context, userID int, input user.ChangeEmailRequest, client models.ClientInfo) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	addr, err := mail.ParseAddress(strings.TrimSpace(input.Email))
+	if err != nil || addr.Name != "" || len(addr.Address) > 100 {
+		return errors.Join(errors2.ErrValidation, errors.New("invalid email"))
+	}
+	email := addr.Address
+
+	u, err := s.checkPassword(ctx, userID, input.Password, client)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(email, u.Email) {
+		return errors.Join(errors2.ErrValidation, errors.New("this is already your email"))
+	}
+
+	owner, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		return err
+	}
+	if owner != nil {
+		return errors2.ErrAccountExists
+	}
+
+	if err := s.verifier.SendEmailChange(ctx, u, email); err != nil {
+		return err
+	}
+
+	logger.Log.Info("Email change requested", "user_id", u.ID)
+	return nil
+}
+
+// Export collects the data stored about the user.
+func (s *ProfileService) Export(ctx context.Context, userID int) (*models.UserDataExport, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	u, err := s.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	news, err := s.newsRepo.ListByAuthor(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.authService.ListSessions(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Log.Info("Personal data exported", "user_id", u.ID)
+	return &models.UserDataExport{User: u, News: news, Sessions: sessions, ExportedAt: time.Now()}, nil
+}
+
+// RequestDeletion schedules the account for deletion after the grace period.
+// Until then the user can still log in and cancel it.
+func (s *ProfileService) RequestDeletion(ctx context.Context, userID int, plainPassword string, client models.ClientInfo) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	u, err := s.checkPassword(ctx, userID, plainPassword, client)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if u.DeletionScheduledAt != nil {
+		return *u.DeletionScheduledAt, nil
+	}
+	if err := requireOtherRoleManager(ctx, s.authz, s.userRepo, u); err != nil {
+		return time.Time{}, err
+	}
+
+	at := time.Now().Add(s.deletionGrace).UTC()
+	if err := s.userRepo.ScheduleDeletion(ctx, u.ID, &at); err != nil {
+		return time.Time{}, err
+	}
+
+	logger.Log.Info("Account deletion scheduled", "user_id", u.ID, "at", at)
+	return at, nil
+}
+
+func (s *ProfileService) CancelDeletion(ctx context.Context, userID int) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	u, err := s.GetProfile(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u.DeletionScheduledAt == nil {
+		return errors2.ErrNotFound
+	}
+	if err := s.userRepo.ScheduleDeletion(ctx, u.ID, nil); err != nil {
+		return err
+	}
+
+	logger.Log.Info("Account deletion cancelled", "user_id", u.ID)
+	return nil
+}
+
+// checkPassword verifies the password of a signed in user. Wrong guesses
+// count towards the login lockout of the account.
+func (s *ProfileService) checkPassword(ctx context.Context, userID int, plain string, client models.ClientInfo) (*models.User, error) {
+	u, err := s.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.guard.Check(ctx, u.Email, client.IP); err != nil {
+		return nil, err
+	}
+	if ok, _ := password.CheckPassword(plain, u.Password); !ok {
+		logger.Log.Warn("Wrong current password", "user_id", u.ID)
+		if err := s.guard.Fail(ctx, u.Email, client.IP); err != nil {
+			logger.Log.Error("Failed to record login failure: " + err.Error())
+		}
+		return nil, errors2.ErrWrongPassword
+	}
+	return u, nil
+}
+
+func validateName(field, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", errors.Join(errors2.ErrValidation, errors.New(field+" must not be empty"))
+	}
+	if utf8.RuneCountInString(value) > maxNameLength {
+		return "", errors.Join(errors2.ErrValidation, errors.New(field+" is too long"))
+	}
+	return value, nil
+}
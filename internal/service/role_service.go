@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/dto/role"
+	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	"news-api/pkg/logger"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,19}$`)
+
+// RoleService lets admins define roles and assign them to users.
+type RoleService struct {
+	roleRepo    interfaces.RoleRepository
+	userRepo    interfaces.UserRepository
+	authz       *Authorizer
+	authService *AuthService
+	audit       *AuditLog
+}
+
+func NewRoleService(
+	roleRepo interfaces.RoleRepository,
+	userRepo interfaces.UserRepository,
+	authz *Authorizer,
+	authService *AuthService,
+	audit *AuditLog,
+) *RoleService {
+	return &RoleService{
+		roleRepo:    roleRepo,
+		userRepo:    userRepo,
+		authz:       authz,
+		authService: authService,
+		audit:       audit,
+	}
+}
+
+func (s *RoleService) ListRoles(ctx context.Context, actor models.Actor) ([]models.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermRolesManage); err != nil {
+		return nil, err
+	}
+	return s.roleRepo.List(ctx)
+}
+
+func (s *RoleService) ListPermissions(ctx context.Context, actor models.Actor) ([]models.Permission, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermRolesManage); err != nil {
+		return nil, err
+	}
+	return s.roleRepo.ListPermissions(ctx)
+}
+
+func (s *RoleService) CreateRole(ctx context.Context, actor models.Actor, input role.CreateRoleRequest) (*models.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermRolesManage); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if !roleNamePattern.MatchString(name) {
+		return nil, errors.Join(errors2.ErrValidation, errors.New("role name must be 2-20 lowercase letters, digits, '-' or '_'"))
+	}
+	perms, err := s.validatePermissions(ctx, input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.roleRepo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors2.ErrRoleExists
+	}
+
+	r := &models.Role{Name: name, Description: strings.TrimSpace(input.Description), Permissions: perms}
+	if err := s.roleRepo.Create(ctx, r); err != nil {
+		return nil, err
+	}
+	s.authz.Invalidate()
+
+	logger.Log.Info("Role created", "role", name, "permissions", perms, "admin_id", actor.UserID)
+	s.audit.Success(ctx, actor, models.AuditRoleCreate, models.AuditTargetRole, name, "permissions: "+strings.Join(perms, ","))
+	return r, nil
+}
+
+// UpdateRole replaces the description and permissions of a role. The admin
+// role is fixed so that nobody can lock everyone out of role management.
+func (s *RoleService) UpdateRole(ctx context.Context, actor models.Actor, name string, input role.UpdateRoleRequest) (*models.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermRolesManage); err != nil {
+		return nil, err
+	}
+
+	r, err := s.roleRepo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, errors2.ErrNotFound
+	}
+	if r.Name == models.RoleAdmin {
+		return nil, errors.Join(errors2.ErrValidation, errors.New("the admin role cannot be changed"))
+	}
+
+	perms, err := s.validatePermissions(ctx, input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	r.Description = strings.TrimSpace(input.Description)
+	r.Permissions = perms
+	if err := s.roleRepo.Update(ctx, r); err != nil {
+		return nil, err
+	}
+	s.authz.Invalidate()
+
+	logger.Log.Info("Role updated", "role", name, "permissions", perms, "admin_id", actor.UserID)
+	s.audit.Success(ctx, actor, models.AuditRoleUpdate, models.AuditTargetRole, name, "permissions: "+strings.Join(perms, ","))
+	return r, nil
+}
+
+func (s *RoleService) DeleteRole(ctx context.Context, actor models.Actor, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermRolesManage); err != nil {
+		return err
+	}
+
+	r, err := s.roleRepo.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	if r == nil {
+		return errors2.ErrNotFound
+	}
+	if r.System {
+		return errors.Join(errors2.ErrValidation, errors.New("system roles cannot be deleted"))
+	}
+	users, err := s.roleRepo.CountUsers(ctx, name)
+	if err != nil {
+		return err
+	}
+	if users > 0 {
+		return errors2.ErrRoleInUse
+	}
+
+	if err := s.roleRepo.Delete(ctx, name); err != nil {
+		return err
+	}
+	s.authz.Invalidate()
+
+	logger.Log.Info("Role deleted", "role", name, "admin_id", actor.UserID)
+	s.audit.Success(ctx, actor, models.AuditRoleDelete, models.AuditTargetRole, name, "")
+	return nil
+}
+
+// AssignRole changes the role of a user. The user's sessions are ended for
+// the change to apply at once.
+func (s *RoleService) AssignRole(ctx context.Context, actor models.Actor, userID int, roleName string) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermUsersManage); err != nil {
+		return err
+	}
+	if userID == actor.UserID {
+		return errors.Join(errors2.ErrValidation, errors.New("you cannot change your own role"))
+	}
+
+	r, err := s.roleRepo.Get(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	if r == nil {
+		return errors.Join(errors2.ErrValidation, fmt.Errorf("unknown role %q", roleName))
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors2.ErrNotFound
+	}
+	if user.Role == r.Name {
+		return nil
+	}
+	// Neither the current nor the new role may carry privileges the actor
+	// lacks.
+	if err := s.authz.RequireOutranks(ctx, actor, user.Role); err != nil {
+		return err
+	}
+	if err := s.authz.RequireOutranks(ctx, actor, r.Name); err != nil {
+		return err
+	}
+	return s.authService.changeRole(ctx, actor, user, r.Name, "")
+}
+
+func (s *RoleService) validatePermissions(ctx context.Context, perms []string) ([]string, error) {
+	known, err := s.roleRepo.ListPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]string, 0, len(perms))
+	for _, p := range perms {
+		p = strings.TrimSpace(p)
+		if !slices.ContainsFunc(known, func(k models.Permission) bool { return k.Name == p }) {
+			return nil, errors.Join(errors2.ErrValidation, fmt.Errorf("unknown permission %q", p))
+		}
+		if !slices.Contains(out, p) {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/dto/taxonomy"
+	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	"news-api/pkg/logger"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxCategoryNameLen = 100
+	maxTagLen          = 50
+	maxNewsCategories  = 10
+	maxNewsTags        = 20
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// TaxonomyService lets everyone browse categories and tags and admins
+// manage them.
+type TaxonomyService struct {
+	repo  interfaces.TaxonomyRepository
+	authz *Authorizer
+	audit *AuditLog
+}
+
+func NewTaxonomyService(repo interfaces.TaxonomyRepository, authz *Authorizer, audit *AuditLog) *TaxonomyService {
+	return &TaxonomyService{repo: repo, authz: authz, audit: audit}
+}
+
+func (s *TaxonomyService) ListCategories(ctx context.Context) ([]models.Category, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return s.repo.ListCategories(ctx)
+}
+
+func (s *TaxonomyService) CreateCategory(ctx context.Context, actor models.Actor, input taxonomy.CategoryRequest) (*models.Category, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermTaxonomyManage); err != nil {
+		return nil, err
+	}
+
+	c := &models.Category{}
+	if err := s.applyCategory(ctx, c, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateCategory(ctx, c); err != nil {
+		return nil, err
+	}
+
+	logger.Log.Info("Category created", "category_id", c.ID, "slug", c.Slug, "admin_id", actor.UserID)
+	s.audit.Success(ctx, actor, models.AuditCategoryCreate, models.AuditTargetCategory, strconv.Itoa(c.ID), c.Slug)
+	return c, nil
+}
+
+// UpdateCategory replaces the fields of the category, moving it under
+// another parent if ParentID changes.
+func (s *TaxonomyService) UpdateCategory(ctx context.Context, actor models.Actor, id int, input taxonomy.CategoryRequest) (*models.Category, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermTaxonomyManage); err != nil {
+		return nil, err
+	}
+
+	c, err := s.repo.GetCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, errors2.ErrNotFound
+	}
+	if err := s.applyCategory(ctx, c, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateCategory(ctx, c); err != nil {
+		return nil, err
+	}
+
+	logger.Log.Info("Category updated", "category_id", c.ID, "slug", c.Slug, "admin_id", actor.UserID)
+	s.audit.Success(ctx, actor, models.AuditCategoryUpdate, models.AuditTargetCategory, strconv.Itoa(c.ID), c.Slug)
+	return c, nil
+}
+
+// DeleteCategory deletes a category without subcategories. Its news stays,
+// without the category.
+func (s *TaxonomyService) DeleteCategory(ctx context.Context, actor models.Actor, id int) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermTaxonomyManage); err != nil {
+		return err
+	}
+
+	categories, err := s.repo.ListCategories(ctx)
+	if err != nil {
+		return err
+	}
+	var c *models.Category
+	for i := range categories {
+		if categories[i].ID == id {
+			c = &categories[i]
+		}
+		if categories[i].ParentID != nil && *categories[i].ParentID == id {
+			return errors2.ErrCategoryInUse
+		}
+	}
+	if c == nil {
+		return errors2.ErrNotFound
+	}
+	if err := s.repo.DeleteCategory(ctx, id); err != nil {
+		return err
+	}
+
+	logger.Log.Info("Category deleted", "category_id", id, "slug", c.Slug, "admin_id", actor.UserID)
+	s.audit.Success(ctx, actor, models.AuditCategoryDelete, models.AuditTargetCategory, strconv.Itoa(id), c.Slug)
+	return nil
+}
+
+// applyCategory validates the input and copies it onto c. The slug must be
+// free and the parent must exist and must not be c or one of its
+// descendants.
+func (s *TaxonomyService) applyCategory(ctx context.Context, c *models.Category, input taxonomy.CategoryRequest) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxCategoryNameLen {
+		return errors.Join(errors2.ErrValidation, errors.New("category name must be 1-100 characters"))
+	}
+	slug := strings.TrimSpace(input.Slug)
+	if len(slug) > maxCategoryNameLen || !slugPattern.MatchString(slug) {
+		return errors.Join(errors2.ErrValidation, errors.New("slug must be lowercase latin letters and digits separated by single '-'"))
+	}
+
+	taken, err := s.repo.GetCategoryBySlug(ctx, slug)
+	if err != nil {
+		return err
+	}
+	if taken != nil && taken.ID != c.ID {
+		return errors2.ErrCategoryExists
+	}
+
+	if input.ParentID != nil {
+		categories, err := s.repo.ListCategories(ctx)
+		if err != nil {
+			return err
+		}
+		parents := make(map[int]*int, len(categories))
+		for _, cat := range categories {
+			parents[cat.ID] = cat.ParentID
+		}
+		if _, ok := parents[*input.ParentID]; !ok {
+			return errors.Join(errors2.ErrValidation, errors.New("parent category does not exist"))
+		}
+		for id := input.ParentID; id != nil; id = parents[*id] {
+			if *id == c.ID {
+				return errors.Join(errors2.ErrValidation, errors.New("a category cannot be placed under itself or its subcategory"))
+			}
+		}
+	}
+
+	c.Name = name
+	c.Slug = slug
+	c.Description = strings.TrimSpace(input.Description)
+	c.ParentID = input.ParentID
+	return nil
+}
+
+func (s *TaxonomyService) ListTags(ctx context.Context) ([]models.Tag, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return s.repo.ListTags(ctx)
+}
+
+// CreateTag adds a tag ahead of its first use; tags are also created when
+// first put on a news item.
+func (s *TaxonomyService) CreateTag(ctx context.Context, actor models.Actor, input taxonomy.TagRequest) (*models.Tag, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermTaxonomyManage); err != nil {
+		return nil, err
+	}
+
+	name, err := normalizeTag(input.Name)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.GetTagByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors2.ErrTagExists
+	}
+
+	t := &models.Tag{Name: name}
+	if err := s.repo.CreateTag(ctx, t); err != nil {
+		return nil, err
+	}
+
+	logger.Log.Info("Tag created", "tag_id", t.ID, "tag", name, "admin_id", actor.UserID)
+	s.audit.Success(ctx, actor, models.AuditTagCreate, models.AuditTargetTag, strconv.Itoa(t.ID), name)
+	return t, nil
+}
+
+// RenameTag renames the tag on every news item that carries it.
+func (s *TaxonomyService) RenameTag(ctx context.Context, actor models.Actor, id int, input taxonomy.TagRequest) (*models.Tag, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermTaxonomyManage); err != nil {
+		return nil, err
+	}
+
+	name, err := normalizeTag(input.Name)
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.repo.GetTag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, errors2.ErrNotFound
+	}
+	existing, err := s.repo.GetTagByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.ID != id {
+		return nil, errors2.ErrTagExists
+	}
+
+	if err := s.repo.RenameTag(ctx, id, name); err != nil {
+		return nil, err
+	}
+
+	logger.Log.Info("Tag renamed", "tag_id", id, "from", t.Name, "to", name, "admin_id", actor.UserID)
+	s.audit.Success(ctx, actor, models.AuditTagUpdate, models.AuditTargetTag, strconv.Itoa(id), t.Name+" -> "+name)
+	t.Name = name
+	return t, nil
+}
+
+// DeleteTag deletes the tag and takes it off every news item.
+func (s *TaxonomyService) DeleteTag(ctx context.Context, actor models.Actor, id int) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermTaxonomyManage); err != nil {
+		return err
+	}
+
+	t, err := s.repo.GetTag(ctx, id)
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return errors2.ErrNotFound
+	}
+	if err := s.repo.DeleteTag(ctx, id); err != nil {
+		return err
+	}
+
+	logger.Log.Info("Tag deleted", "tag_id", id, "tag", t.Name, "admin_id", actor.UserID)
+	s.audit.Success(ctx, actor, models.AuditTagDelete, models.AuditTargetTag, strconv.Itoa(id), t.Name)
+	return nil
+}
+
+// normalizeTag lowercases the tag and collapses its whitespace, so that
+// "Local  News" and "local news" are the same tag.
+func normalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+	if tag == "" || utf8.RuneCountInString(tag) > maxTagLen {
+		return "", errors.Join(errors2.ErrValidation, fmt.Errorf("tags must be 1-%d characters", maxTagLen))
+	}
+	return tag, nil
+}
+
+// normalizeNewsTaxonomy checks the categories and tags given for a news item
+// and removes duplicates. Nil lists mean "unchanged" and are left nil.
+func normalizeNewsTaxonomy(ctx context.Context, repo interfaces.TaxonomyRepository, n *models.News) error {
+	if n.CategoryIDs != nil {
+		ids := make([]int, 0, len(n.CategoryIDs))
+		seen := make(map[int]bool, len(n.CategoryIDs))
+		for _, id := range n.CategoryIDs {
+			if id <= 0 {
+				return errors.Join(errors2.ErrValidation, errors.New("invalid category id"))
+			}
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) > maxNewsCategories {
+			return errors.Join(errors2.ErrValidation, fmt.Errorf("at most %d categories per news", maxNewsCategories))
+		}
+		if len(ids) > 0 {
+			found, err := repo.CountCategories(ctx, ids)
+			if err != nil {
+				return err
+			}
+			if found != len(ids) {
+				return errors.Join(errors2.ErrValidation, errors.New("unknown category id"))
+			}
+		}
+		n.CategoryIDs = ids
+	}
+
+	if n.Tags != nil {
+		tags := make([]string, 0, len(n.Tags))
+		for _, tag := range n.Tags {
+			tag, err := normalizeTag(tag)
+			if err != nil {
+				return err
+			}
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		if len(tags) > maxNewsTags {
+			return errors.Join(errors2.ErrValidation, fmt.Errorf("at most %d tags per news", maxNewsTags))
+		}
+		n.Tags = tags
+	}
+	return nil
+}
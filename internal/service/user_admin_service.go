@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/dto/user"
+	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	"news-api/pkg/logger"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const maxSuspendReasonLength = 500
+
+// UserAdminService lets admins browse, suspend and delete user accounts.
+type UserAdminService struct {
+	userRepo    interfaces.UserRepository
+	authz       *Authorizer
+	authService *AuthService
+	deleter     *AccountDeleter
+	audit       *AuditLog
+}
+
+func NewUserAdminService(
+	userRepo interfaces.UserRepository,
+	authz *Authorizer,
+	authService *AuthService,
+	deleter *AccountDeleter,
+	audit *AuditLog,
+) *UserAdminService {
+	return &UserAdminService{
+		userRepo:    userRepo,
+		authz:       authz,
+		authService: authService,
+		deleter:     deleter,
+		audit:       audit,
+	}
+}
+
+func (s *UserAdminService) ListUsers(ctx context.Context, actor models.Actor, params models.UserListParams) ([]models.User, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermUsersManage); err != nil {
+		return nil, 0, err
+	}
+	params.Normalize()
+	return s.userRepo.List(ctx, params)
+}
+
+func (s *UserAdminService) GetUser(ctx context.Context, actor models.Actor, userID int) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermUsersManage); err != nil {
+		return nil, err
+	}
+	return s.getUser(userID)
+}
+
+// SuspendUser blocks the user from logging in and ends their sessions. API
+// keys of the user stop working as well.
+func (s *UserAdminService) SuspendUser(ctx context.Context, actor models.Actor, userID int, reason string) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermUsersManage); err != nil {
+		return err
+	}
+	if userID == actor.UserID {
+		return errors.Join(errors2.ErrValidation, errors.New("you cannot suspend yourself"))
+	}
+	reason = strings.TrimSpace(reason)
+	if len(reason) > maxSuspendReasonLength {
+		return errors.Join(errors2.ErrValidation, errors.New("reason is too long"))
+	}
+
+	u, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.requireRemovable(ctx, actor, u); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.Suspend(ctx, u.ID, reason); err != nil {
+		return err
+	}
+	if err := s.authService.RevokeAllUserTokens(ctx, u.ID); err != nil {
+		return err
+	}
+
+	logger.Log.Info("User suspended", "user_id", u.ID, "reason", reason, "admin_id", actor.UserID)
+	s.audit.Success(ctx, actor, models.AuditUserSuspend, models.AuditTargetUser, strconv.Itoa(u.ID), reason)
+	return nil
+}
+
+func (s *UserAdminService) UnsuspendUser(ctx context.Context, actor models.Actor, userID int) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermUsersManage); err != nil {
+		return err
+	}
+
+	u, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if !u.Suspended() {
+		return nil
+	}
+
+	if err := s.userRepo.Unsuspend(ctx, u.ID); err != nil {
+		return err
+	}
+
+	logger.Log.Info("User unsuspended", "user_id", u.ID, "admin_id", actor.UserID)
+	s.audit.Success(ctx, actor, models.AuditUserUnsuspend, models.AuditTargetUser, strconv.Itoa(u.ID), "")
+	return nil
+}
+
+// DeleteUser removes the account at once. The news of the user is deleted,
+// anonymised or transferred to another user, the acting admin unless
+// input.TransferTo says otherwise.
+func (s *UserAdminService) DeleteUser(ctx context.Context, actor models.Actor, userID int, input user.DeleteUserInput) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := s.authz.Require(ctx, actor, models.PermUsersManage); err != nil {
+		return err
+	}
+	if userID == actor.UserID {
+		return errors.Join(errors2.ErrValidation, errors.New("you cannot delete yourself"))
+	}
+
+	u, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.requireRemovable(ctx, actor, u); err != nil {
+		return err
+	}
+
+	transferTo := 0
+	switch input.News {
+	case models.NewsDelete, models.NewsAnonymize:
+		if input.TransferTo != nil {
+			return errors.Join(errors2.ErrValidation, errors.New("transfer_to requires news=transfer"))
+		}
+	case models.NewsTransfer:
+		transferTo = actor.UserID
+		if input.TransferTo != nil {
+			transferTo = *input.TransferTo
+		}
+		if err := s.checkRecipient(transferTo, u.ID); err != nil {
+			return err
+		}
+	default:
+		return errors.Join(errors2.ErrValidation, errors.New("news must be 'delete', 'transfer' or 'anonymize'"))
+	}
+
+	if err := s.deleter.Delete(ctx, u, input.News, transferTo); err != nil {
+		return err
+	}
+
+	logger.Log.Info("User deleted by admin", "user_id", u.ID, "news", input.News, "transfer_to", transferTo, "admin_id", actor.UserID)
+	detail := "news: " + input.News
+	if transferTo != 0 {
+		detail += " to " + strconv.Itoa(transferTo)
+	}
+	s.audit.Success(ctx, actor, models.AuditUserDelete, models.AuditTargetUser, strconv.Itoa(u.ID), detail)
+	return nil
+}
+
+// requireRemovable refuses to suspend or delete a user who holds privileges
+// the actor does not, or the last active user who can manage roles.
+func (s *UserAdminService) requireRemovable(ctx context.Context, actor models.Actor, u *models.User) error {
+	if err := s.authz.RequireOutranks(ctx, actor, u.Role); err != nil {
+		return err
+	}
+	if u.Suspended() {
+		return nil
+	}
+	return requireOtherRoleManager(ctx, s.authz, s.userRepo, u)
+}
+
+// requireOtherRoleManager returns a validation error if u is the only active
+// user who can manage roles, so that taking the permission away from u would
+// leave nobody able to grant it again.
+func requireOtherRoleManager(ctx context.Context, authz *Authorizer, userRepo interfaces.UserRepository, u *models.User) error {
+	roles, err := authz.RolesWith(ctx, models.PermRolesManage)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(roles, u.Role) {
+		return nil
+	}
+	holders, err := userRepo.CountActiveInRoles(ctx, roles)
+	if err != nil {
+		return err
+	}
+	if holders <= 1 {
+		return errors.Join(errors2.ErrValidation, errors.New("this is the last active user who can manage roles"))
+	}
+	return nil
+}
+
+func (s *UserAdminService) checkRecipient(recipientID, deletedID int) error {
+	if recipientID == deletedID {
+		return errors.Join(errors2.ErrValidation, errors.New("news cannot be transferred to the deleted user"))
+	}
+	recipient, err := s.userRepo.GetByID(recipientID)
+	if err != nil {
+		return err
+	}
+	if recipient == nil {
+		return errors.Join(errors2.ErrValidation, errors.New("transfer_to user not found"))
+	}
+	return nil
+}
+
+func (s *UserAdminService) getUser(userID int) (*models.User, error) {
+	u, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, errors2.ErrNotFound
+	}
+	return u, nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	errors2 "news-api/internal/dto/errors"
+	"news-api/internal/models"
+	"news-api/internal/repository/interfaces"
+	"news-api/pkg/logger"
+	"news-api/pkg/mailer"
+	"news-api/pkg/token"
+	"time"
+)
+
+type VerificationService struct {
+	userRepo         interfaces.UserRepository
+	verificationRepo interfaces.EmailVerificationRepository
+	mailer           mailer.Mailer
+	frontendURL      string
+	tokenTTL         time.Duration
+}
+
+func NewVerificationService(
+	userRepo interfaces.UserRepository,
+	verificationRepo interfaces.EmailVerificationRepository,
+	mailer mailer.Mailer,
+	frontendURL string,
+	tokenTTL time.Duration,
+) *VerificationService {
+	return &VerificationService{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		mailer:           mailer,
+		frontendURL:      frontendURL,
+		tokenTTL:         tokenTTL,
+	}
+}
+
+// SendVerification emails a link that proves the user owns email. Earlier
+// links of the user stop working.
+func (s *VerificationService) SendVerification(ctx context.Context, user *models.User, email string) error {
+	return s.sendLink(ctx, user, email, "Confirm your email",
+		"Hello %s,\n\nPlease confirm your email address by opening the link below. It is valid for %d hours.\n\n%s\n\nIf you did not create an account, ignore this email.\n",
+	)
+}
+
+// SendEmailChange emails a confirmation link to the new address of the user.
+// The email of the account changes only when the link is opened.
+func (s *VerificationService) SendEmailChange(ctx context.Context, user *models.User, email string) error {
+	return s.sendLink(ctx, user, email, "Confirm your new email",
+		"Hello %s,\n\nPlease confirm that this is the new email address of your account by opening the link below. It is valid for %d hours.\n\n%s\n\nIf you did not ask to change your email, ignore this email.\n",
+	)
+}
+
+func (s *VerificationService) sendLink(ctx context.Context, user *models.User, email, subject, body string) error {
+	plain, hash, err := token.NewSecret()
+	if err != nil {
+		logger.Log.Error("Failed to generate verification token: " + err.Error())
+		return err
+	}
+
+	if err := s.verificationRepo.InvalidateByUser(ctx, user.ID); err != nil {
+		return err
+	}
+	t := models.EmailVerificationToken{
+		UserID:    user.ID,
+		Email:     email,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(s.tokenTTL),
+	}
+	if err := s.verificationRepo.Create(ctx, &t); err != nil {
+		return err
+	}
+
+	link := s.frontendURL + "/verify-email?token=" + url.QueryEscape(plain)
+	msg := mailer.Message{
+		To:      email,
+		Subject: subject,
+		Body:    fmt.Sprintf(body, user.FirstName, int(s.tokenTTL.Hours()), link),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		logger.Log.Error("Failed to send verification email", "error", err, "user_id", user.ID)
+		return err
+	}
+
+	logger.Log.Info("Verification email sent", "user_id", user.ID)
+	return nil
+}
+
+func (s *VerificationService) VerifyEmail(ctx context.Context, verificationToken string) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	t, err := s.verificationRepo.Consume(ctx, token.HashSecret(verificationToken))
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		logger.Log.Warn("Email verification with invalid or expired token")
+		return errors2.ErrInvalidToken
+	}
+
+	// The address may have been taken by another account since the link of
+	// an email change was sent.
+	owner, err := s.userRepo.GetByEmail(t.Email)
+	if err != nil {
+		return err
+	}
+	if owner != nil && owner.ID != t.UserID {
+		logger.Log.Warn("Email change refused: address taken", "user_id", t.UserID)
+		return errors2.ErrAccountExists
+	}
+
+	if err := s.userRepo.MarkEmailVerified(ctx, t.UserID, t.Email); err != nil {
+		return err
+	}
+
+	logger.Log.Info("Email verified", "user_id", t.UserID)
+	return nil
+}
+
+func (s *VerificationService) ResendVerification(ctx context.Context, userID int) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors2.ErrNotFound
+	}
+	if user.EmailVerified() {
+		return errors2.ErrAlreadyVerified
+	}
+
+	return s.SendVerification(ctx, user, user.Email)
+}
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+-- Accounts created before verification existed are trusted as they are.
+UPDATE users SET email_verified_at = COALESCE(created_at, now());
+
+CREATE TABLE email_verification_tokens
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INT          NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    email      VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64)  NOT NULL UNIQUE,
+    expires_at TIMESTAMP    NOT NULL,
+    used_at    TIMESTAMP,
+    created_at TIMESTAMP DEFAULT now()
+);
+CREATE INDEX idx_email_verification_tokens_user_id ON email_verification_tokens (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE email_verification_tokens;
+ALTER TABLE users DROP COLUMN email_verified_at;
+-- +goose StatementEnd
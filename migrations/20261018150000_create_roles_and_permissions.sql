@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE roles
+(
+    name        VARCHAR(20) PRIMARY KEY,
+    description TEXT    NOT NULL DEFAULT '',
+    is_system   BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at  TIMESTAMP        DEFAULT now()
+);
+
+CREATE TABLE permissions
+(
+    name        VARCHAR(64) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE role_permissions
+(
+    role       VARCHAR(20) NOT NULL REFERENCES roles (name) ON UPDATE CASCADE ON DELETE CASCADE,
+    permission VARCHAR(64) NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description, is_system)
+VALUES ('admin', 'Full access', TRUE),
+       ('editor', 'Writes and edits own news', TRUE);
+
+-- Keep any other role already stored on users, without permissions.
+INSERT INTO roles (name)
+SELECT DISTINCT role
+FROM users
+WHERE role NOT IN ('admin', 'editor');
+
+INSERT INTO permissions (name, description)
+VALUES ('news:create', 'Create news'),
+       ('news:update:own', 'Edit own news'),
+       ('news:update:any', 'Edit news of any author'),
+       ('news:delete:own', 'Delete own news'),
+       ('news:delete:any', 'Delete news of any author'),
+       ('users:manage', 'Manage user accounts and assign roles'),
+       ('roles:manage', 'Define roles and their permissions');
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('admin', 'news:create'),
+       ('admin', 'news:update:own'),
+       ('admin', 'news:delete:any'),
+       ('admin', 'users:manage'),
+       ('admin', 'roles:manage'),
+       ('editor', 'news:create'),
+       ('editor', 'news:update:own');
+
+ALTER TABLE users
+    ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES roles (name) ON UPDATE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP CONSTRAINT fk_users_role;
+DROP TABLE role_permissions;
+DROP TABLE permissions;
+DROP TABLE roles;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- avatar keeps the public URL of the avatar; avatar_key is the storage key
+-- prefix of an uploaded avatar and is empty for external pictures.
+ALTER TABLE users ADD COLUMN avatar_key TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN avatar_key;
+-- +goose StatementEnd
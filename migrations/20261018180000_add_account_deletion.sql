@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN deletion_scheduled_at TIMESTAMP;
+CREATE INDEX idx_users_deletion_scheduled_at ON users (deletion_scheduled_at) WHERE deletion_scheduled_at IS NOT NULL;
+
+-- News outlives its author: deleting a user no longer cascades, the news
+-- keeps a NULL author unless it was transferred or deleted explicitly.
+ALTER TABLE news ALTER COLUMN author_id DROP NOT NULL;
+ALTER TABLE news DROP CONSTRAINT news_author_id_fkey;
+ALTER TABLE news ADD CONSTRAINT news_author_id_fkey
+    FOREIGN KEY (author_id) REFERENCES users (id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM news WHERE author_id IS NULL;
+ALTER TABLE news DROP CONSTRAINT news_author_id_fkey;
+ALTER TABLE news ADD CONSTRAINT news_author_id_fkey
+    FOREIGN KEY (author_id) REFERENCES users (id) ON DELETE CASCADE;
+ALTER TABLE news ALTER COLUMN author_id SET NOT NULL;
+
+DROP INDEX idx_users_deletion_scheduled_at;
+ALTER TABLE users DROP COLUMN deletion_scheduled_at;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Audit trail of admins acting as other users. Rows outlive both accounts.
+CREATE TABLE impersonations
+(
+    id         SERIAL PRIMARY KEY,
+    admin_id   INT REFERENCES users (id) ON DELETE SET NULL,
+    user_id    INT REFERENCES users (id) ON DELETE SET NULL,
+    reason     TEXT        NOT NULL,
+    token_id   VARCHAR(64) NOT NULL,
+    ip         VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent TEXT        NOT NULL DEFAULT '',
+    expires_at TIMESTAMP   NOT NULL,
+    created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX idx_impersonations_admin_id ON impersonations (admin_id);
+CREATE INDEX idx_impersonations_user_id ON impersonations (user_id);
+
+INSERT INTO permissions (name, description)
+VALUES ('users:impersonate', 'Act as another user with a short-lived token');
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('admin', 'users:impersonate');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'users:impersonate';
+DROP TABLE impersonations;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Append-only log of security relevant events. Actor and target ids are kept
+-- without foreign keys so that the rows outlive the accounts they name.
+CREATE TABLE audit_events
+(
+    id              BIGSERIAL PRIMARY KEY,
+    actor_id        INT,
+    impersonator_id INT,
+    action          VARCHAR(64) NOT NULL,
+    target_type     VARCHAR(32) NOT NULL DEFAULT '',
+    target_id       VARCHAR(255) NOT NULL DEFAULT '',
+    outcome         VARCHAR(16) NOT NULL,
+    detail          TEXT        NOT NULL DEFAULT '',
+    ip              VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent      TEXT        NOT NULL DEFAULT '',
+    created_at      TIMESTAMP   NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_audit_events_created_at ON audit_events (created_at);
+CREATE INDEX idx_audit_events_actor_id ON audit_events (actor_id);
+CREATE INDEX idx_audit_events_action ON audit_events (action);
+CREATE INDEX idx_audit_events_target ON audit_events (target_type, target_id);
+
+CREATE FUNCTION audit_events_append_only() RETURNS trigger AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE
+    ON audit_events
+    FOR EACH ROW
+EXECUTE FUNCTION audit_events_append_only();
+
+INSERT INTO permissions (name, description)
+VALUES ('audit:read', 'View and export the security audit log');
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('admin', 'audit:read');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'audit:read';
+DROP TABLE audit_events;
+DROP FUNCTION audit_events_append_only();
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE news
+    ADD COLUMN status       VARCHAR(16) NOT NULL DEFAULT 'draft',
+    ADD COLUMN published_at TIMESTAMP;
+
+-- Everything written before the workflow existed was public already.
+UPDATE news SET status = 'published', published_at = created_at;
+
+ALTER TABLE news
+    ADD CONSTRAINT news_status_check CHECK (status IN ('draft', 'in_review', 'published', 'archived'));
+
+CREATE INDEX idx_news_status_published_at ON news (status, published_at DESC);
+
+INSERT INTO permissions (name, description)
+VALUES ('news:publish', 'Review, publish and archive news');
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('admin', 'news:publish');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'news:publish';
+DROP INDEX idx_news_status_published_at;
+ALTER TABLE news
+    DROP CONSTRAINT news_status_check,
+    DROP COLUMN published_at,
+    DROP COLUMN status;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE news
+    ADD COLUMN publish_at   TIMESTAMP,
+    ADD COLUMN unpublish_at TIMESTAMP;
+
+ALTER TABLE news
+    DROP CONSTRAINT news_status_check,
+    ADD CONSTRAINT news_status_check CHECK (status IN ('draft', 'in_review', 'scheduled', 'published', 'archived'));
+
+-- What the scheduler looks for on every run.
+CREATE INDEX idx_news_publish_at ON news (publish_at) WHERE status = 'scheduled';
+CREATE INDEX idx_news_unpublish_at ON news (unpublish_at) WHERE status = 'published' AND unpublish_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE news SET status = 'in_review' WHERE status = 'scheduled';
+DROP INDEX idx_news_unpublish_at;
+DROP INDEX idx_news_publish_at;
+ALTER TABLE news
+    DROP CONSTRAINT news_status_check,
+    ADD CONSTRAINT news_status_check CHECK (status IN ('draft', 'in_review', 'published', 'archived')),
+    DROP COLUMN unpublish_at,
+    DROP COLUMN publish_at;
+-- +goose StatementEnd
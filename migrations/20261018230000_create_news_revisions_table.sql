@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every saved version of a news text. Revision 1 is the text as created;
+-- each update and restore adds the next one.
+CREATE TABLE news_revisions
+(
+    id            SERIAL PRIMARY KEY,
+    news_id       INT          NOT NULL REFERENCES news (id) ON DELETE CASCADE,
+    revision      INT          NOT NULL,
+    title         VARCHAR(255) NOT NULL,
+    description   TEXT         NOT NULL,
+    editor_id     INT REFERENCES users (id) ON DELETE SET NULL,
+    restored_from INT,
+    created_at    TIMESTAMP DEFAULT now(),
+    UNIQUE (news_id, revision)
+);
+
+-- Earlier edits were not kept; the current text becomes the first revision.
+INSERT INTO news_revisions (news_id, revision, title, description, editor_id, created_at)
+SELECT id, 1, title, description, author_id, updated_at
+FROM news;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE news_revisions;
+-- +goose StatementEnd
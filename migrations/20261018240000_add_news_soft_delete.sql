@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Deleted news stays in the trash until the retention job purges it.
+ALTER TABLE news
+    ADD COLUMN deleted_at TIMESTAMP,
+    ADD COLUMN deleted_by INT REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_news_deleted_at ON news (deleted_at) WHERE deleted_at IS NOT NULL;
+
+INSERT INTO permissions (name, description)
+VALUES ('news:trash', 'List deleted news and restore it');
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('admin', 'news:trash');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'news:trash';
+DELETE FROM news WHERE deleted_at IS NOT NULL;
+DROP INDEX idx_news_deleted_at;
+ALTER TABLE news
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Sections of the site. A category with subcategories cannot be deleted.
+CREATE TABLE categories
+(
+    id          SERIAL PRIMARY KEY,
+    parent_id   INT REFERENCES categories (id) ON DELETE RESTRICT,
+    name        VARCHAR(100) NOT NULL,
+    slug        VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT         NOT NULL DEFAULT '',
+    created_at  TIMESTAMP DEFAULT now(),
+    updated_at  TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX idx_categories_parent_id ON categories (parent_id);
+
+-- Free-form tags, created on first use. Names are stored lowercased.
+CREATE TABLE tags
+(
+    id         SERIAL PRIMARY KEY,
+    name       VARCHAR(50) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE TABLE news_categories
+(
+    news_id     INT NOT NULL REFERENCES news (id) ON DELETE CASCADE,
+    category_id INT NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    PRIMARY KEY (news_id, category_id)
+);
+
+CREATE INDEX idx_news_categories_category_id ON news_categories (category_id);
+
+CREATE TABLE news_tags
+(
+    news_id INT NOT NULL REFERENCES news (id) ON DELETE CASCADE,
+    tag_id  INT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (news_id, tag_id)
+);
+
+CREATE INDEX idx_news_tags_tag_id ON news_tags (tag_id);
+
+INSERT INTO permissions (name, description)
+VALUES ('taxonomy:manage', 'Create, rename and delete categories and tags');
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('admin', 'taxonomy:manage');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'taxonomy:manage';
+DROP TABLE news_tags;
+DROP TABLE news_categories;
+DROP TABLE tags;
+DROP TABLE categories;
+-- +goose StatementEnd
@@ -0,0 +1,282 @@
+// Package mockidp is a minimal OpenID provider for local development. Its
+// login page accepts any identity typed into the form, so it must never be
+// exposed outside a developer machine.
+package mockidp
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	keyID   = "mock-idp"
+	codeTTL = time.Minute
+	idTTL   = 5 * time.Minute
+)
+
+type grant struct {
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	claims        jwt.MapClaims
+	expiresAt     time.Time
+}
+
+type Server struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+
+	key   *rsa.PrivateKey
+	mux   *http.ServeMux
+	mu    sync.Mutex
+	codes map[string]grant
+}
+
+func New(issuer, clientID, clientSecret string) (*Server, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		Issuer:       strings.TrimSuffix(issuer, "/"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		key:          key,
+		mux:          http.NewServeMux(),
+		codes:        make(map[string]grant),
+	}
+	s.mux.HandleFunc("GET /.well-known/openid-configuration", s.discovery)
+	s.mux.HandleFunc("GET /jwks", s.jwks)
+	s.mux.HandleFunc("GET /authorize", s.loginPage)
+	s.mux.HandleFunc("POST /authorize", s.authorize)
+	s.mux.HandleFunc("POST /token", s.token)
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.Issuer,
+		"authorization_endpoint":                s.Issuer + "/authorize",
+		"token_endpoint":                        s.Issuer + "/token",
+		"jwks_uri":                              s.Issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, _ *http.Request) {
+	pub := s.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+var loginTemplate = template.Must(template.New("login").Parse(`<!doctype html>
+<html><head><title>Mock IdP</title></head>
+<body>
+<h1>Mock IdP login</h1>
+<form method="post" action="/authorize">
+{{range $k, $v := .Params}}<input type="hidden" name="{{$k}}" value="{{$v}}">
+{{end}}
+<p><label>Subject <input name="sub" value="mock-user-1"></label></p>
+<p><label>Email <input name="email" value="jane.doe@example.com"></label></p>
+<p><label>Email verified <input type="checkbox" name="email_verified" value="true" checked></label></p>
+<p><label>Given name <input name="given_name" value="Jane"></label></p>
+<p><label>Family name <input name="family_name" value="Doe"></label></p>
+<p><label>Groups (comma separated) <input name="groups" value="newsroom-editors"></label></p>
+<p><button type="submit">Sign in</button></p>
+</form>
+</body></html>`))
+
+func (s *Server) loginPage(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if msg := s.checkAuthorizeRequest(q); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	params := map[string]string{}
+	for _, name := range []string{"client_id", "redirect_uri", "state", "nonce", "code_challenge"} {
+		params[name] = q.Get(name)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = loginTemplate.Execute(w, map[string]interface{}{"Params": params})
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	f := r.PostForm
+	f.Set("response_type", "code")
+	f.Set("code_challenge_method", "S256")
+	if msg := s.checkAuthorizeRequest(f); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	if f.Get("sub") == "" {
+		http.Error(w, "sub is required", http.StatusBadRequest)
+		return
+	}
+
+	var groups []string
+	for _, g := range strings.Split(f.Get("groups"), ",") {
+		if g = strings.TrimSpace(g); g != "" {
+			groups = append(groups, g)
+		}
+	}
+	claims := jwt.MapClaims{
+		"sub":            f.Get("sub"),
+		"email":          f.Get("email"),
+		"email_verified": f.Get("email_verified") == "true",
+		"given_name":     f.Get("given_name"),
+		"family_name":    f.Get("family_name"),
+		"name":           strings.TrimSpace(f.Get("given_name") + " " + f.Get("family_name")),
+		"groups":         groups,
+	}
+
+	code := randomString()
+	s.mu.Lock()
+	s.codes[code] = grant{
+		redirectURI:   f.Get("redirect_uri"),
+		codeChallenge: f.Get("code_challenge"),
+		nonce:         f.Get("nonce"),
+		claims:        claims,
+		expiresAt:     time.Now().Add(codeTTL),
+	}
+	s.mu.Unlock()
+
+	target, _ := url.Parse(f.Get("redirect_uri"))
+	q := target.Query()
+	q.Set("code", code)
+	q.Set("state", f.Get("state"))
+	target.RawQuery = q.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request")
+		return
+	}
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != s.ClientID || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(s.ClientSecret)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type")
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	s.mu.Lock()
+	g, found := s.codes[code]
+	delete(s.codes, code)
+	s.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	switch {
+	case !found || time.Now().After(g.expiresAt):
+		tokenError(w, "invalid_grant")
+		return
+	case g.redirectURI != r.PostForm.Get("redirect_uri"):
+		tokenError(w, "invalid_grant")
+		return
+	case base64.RawURLEncoding.EncodeToString(sum[:]) != g.codeChallenge:
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{}
+	for k, v := range g.claims {
+		claims[k] = v
+	}
+	claims["iss"] = s.Issuer
+	claims["aud"] = s.ClientID
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(idTTL).Unix()
+	claims["nonce"] = g.nonce
+
+	t := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	t.Header["kid"] = keyID
+	idToken, err := t.SignedString(s.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   int(idTTL.Seconds()),
+		"id_token":     idToken,
+	})
+}
+
+func (s *Server) checkAuthorizeRequest(q url.Values) string {
+	switch {
+	case q.Get("response_type") != "code":
+		return "unsupported response_type"
+	case q.Get("client_id") != s.ClientID:
+		return "unknown client_id"
+	case q.Get("redirect_uri") == "":
+		return "redirect_uri is required"
+	case q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256":
+		return "PKCE with S256 is required"
+	default:
+		return ""
+	}
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
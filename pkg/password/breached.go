@@ -0,0 +1,110 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	sha1HexLength = 40
+	prefixLength  = 5
+)
+
+// BreachedList looks passwords up in a local copy of a breached-password
+// corpus in the k-anonymity range format of Have I Been Pwned: SHA-1 hashes
+// grouped by their first five hex characters, every range holding lines
+// "SUFFIX:COUNT" with the remaining 35 characters.
+//
+// The list is either a directory with one range file per prefix
+// (5BAA6.txt, as written by the HIBP downloader), read on demand, or a single
+// file of full hashes ("HASH" or "HASH:COUNT" per line), loaded into memory.
+type BreachedList struct {
+	dir    string
+	ranges map[string]map[string]struct{}
+}
+
+func LoadBreachedList(path string) (*BreachedList, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return &BreachedList{dir: path}, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	l := &BreachedList{ranges: map[string]map[string]struct{}{}}
+	err = scanHashes(f, func(hash string) bool {
+		if len(hash) != sha1HexLength {
+			return true
+		}
+		prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+		if l.ranges[prefix] == nil {
+			l.ranges[prefix] = map[string]struct{}{}
+		}
+		l.ranges[prefix][suffix] = struct{}{}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read breached passwords: %w", err)
+	}
+	return l, nil
+}
+
+// Contains reports whether the password is in the list.
+func (l *BreachedList) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+
+	if l.dir == "" {
+		_, ok := l.ranges[prefix][suffix]
+		return ok, nil
+	}
+
+	f, err := os.Open(filepath.Join(l.dir, prefix+".txt"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	found := false
+	err = scanHashes(f, func(s string) bool {
+		found = s == suffix
+		return !found
+	})
+	return found, err
+}
+
+// scanHashes calls fn with the upper-cased hash part of every line whose
+// count is not zero (range files are padded with such lines) until fn
+// returns false.
+func scanHashes(r io.Reader, fn func(hash string) bool) error {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		hash, count, _ := strings.Cut(strings.TrimSpace(sc.Text()), ":")
+		if hash == "" || count == "0" {
+			continue
+		}
+		if !fn(strings.ToUpper(hash)) {
+			return nil
+		}
+	}
+	return sc.Err()
+}
@@ -1,13 +1,144 @@
 package password
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Hash algorithms. Hashes are stored in the PHC string format
+// ("$argon2id$v=19$m=...,t=...,p=...$salt$key") or as bcrypt's own
+// "$2a$cost$..." strings, so that every hash names how it was made.
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+	argon2MaxMemory  = 4 * 1024 * 1024 // KiB
+)
+
+// Params selects how new passwords are hashed. Hashes made with other
+// parameters still verify but are reported as outdated.
+type Params struct {
+	Algorithm string
+
+	// argon2id
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+
+	// bcrypt
+	BcryptCost int
+}
+
+// DefaultParams follow the second recommended argon2id setting of RFC 9106.
+var DefaultParams = Params{
+	Algorithm:   AlgorithmArgon2id,
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 4,
+	BcryptCost:  bcrypt.DefaultCost,
+}
+
+var current atomic.Pointer[Params]
+
+func init() {
+	p := DefaultParams
+	current.Store(&p)
+}
+
+// Configure sets the parameters for new hashes. It is meant to be called once
+// at startup.
+func Configure(p Params) error {
+	switch p.Algorithm {
+	case AlgorithmArgon2id:
+		if p.Parallelism < 1 || p.Iterations < 1 || p.Memory < 8*uint32(p.Parallelism) || p.Memory > argon2MaxMemory {
+			return errors.New("invalid argon2id parameters")
+		}
+	case AlgorithmBcrypt:
+		if p.BcryptCost < bcrypt.MinCost || p.BcryptCost > bcrypt.MaxCost {
+			return fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	default:
+		return fmt.Errorf("unknown password hash algorithm %q", p.Algorithm)
+	}
+	current.Store(&p)
+	return nil
+}
 
 func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return string(bytes), err
+	p := current.Load()
+	if p.Algorithm == AlgorithmBcrypt {
+		bytes, err := bcrypt.GenerateFromPassword([]byte(password), p.BcryptCost)
+		return string(bytes), err
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, argon2KeyLength)
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		AlgorithmArgon2id, argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+	), nil
 }
 
-func CheckPassword(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+// CheckPassword reports whether the password matches the hash and, if it
+// does, whether the hash was made with other than the current parameters and
+// should be replaced by a new HashPassword.
+func CheckPassword(password, hash string) (ok bool, outdated bool) {
+	p := current.Load()
+	if strings.HasPrefix(hash, "$"+AlgorithmArgon2id+"$") {
+		return checkArgon2id(password, hash, p)
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return false, false
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return true, p.Algorithm != AlgorithmBcrypt || err != nil || cost != p.BcryptCost
+}
+
+func checkArgon2id(password, hash string, p *Params) (bool, bool) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return false, false
+	}
+	var version int
+	var memory, iterations uint32
+	var parallelism uint8
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, false
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &parallelism); err != nil {
+		return false, false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return false, false
+	}
+
+	got := argon2.IDKey([]byte(password), salt, iterations, memory, parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(got, key) != 1 {
+		return false, false
+	}
+	outdated := p.Algorithm != AlgorithmArgon2id ||
+		memory != p.Memory || iterations != p.Iterations || parallelism != p.Parallelism ||
+		len(salt) != argon2SaltLength || len(key) != argon2KeyLength
+	return true, outdated
 }
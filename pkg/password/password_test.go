@@ -0,0 +1,120 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Cheap settings keep the tests fast; only their being different matters.
+var (
+	testArgon2 = Params{Algorithm: AlgorithmArgon2id, Memory: 64, Iterations: 1, Parallelism: 1}
+	testBcrypt = Params{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MinCost}
+)
+
+// configure sets p for the test and restores the defaults afterwards.
+func configure(t *testing.T, p Params) {
+	t.Helper()
+	if err := Configure(p); err != nil {
+		t.Fatalf("Configure(%+v): %v", p, err)
+	}
+	t.Cleanup(func() {
+		if err := Configure(DefaultParams); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// hashWith returns a hash of password made with p.
+func hashWith(t *testing.T, p Params, password string) string {
+	t.Helper()
+	configure(t, p)
+	hash, err := HashPassword(password)
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	return hash
+}
+
+func TestHashPasswordFormat(t *testing.T) {
+	argon := hashWith(t, testArgon2, "secret")
+	if !strings.HasPrefix(argon, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("argon2id hash = %q", argon)
+	}
+	bc := hashWith(t, testBcrypt, "secret")
+	if !strings.HasPrefix(bc, "$2a$04$") {
+		t.Errorf("bcrypt hash = %q", bc)
+	}
+
+	configure(t, testArgon2)
+	a, _ := HashPassword("secret")
+	b, _ := HashPassword("secret")
+	if a == b {
+		t.Error("two hashes of the same password share a salt")
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	argon := hashWith(t, testArgon2, "correct horse")
+	otherArgon := hashWith(t, Params{Algorithm: AlgorithmArgon2id, Memory: 128, Iterations: 2, Parallelism: 1}, "correct horse")
+	bc := hashWith(t, testBcrypt, "correct horse")
+	otherBcrypt := hashWith(t, Params{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MinCost + 1}, "correct horse")
+
+	tests := []struct {
+		name         string
+		current      Params
+		password     string
+		hash         string
+		wantOK       bool
+		wantOutdated bool
+	}{
+		{"argon2id current", testArgon2, "correct horse", argon, true, false},
+		{"argon2id wrong password", testArgon2, "wrong horse", argon, false, false},
+		{"argon2id other parameters", testArgon2, "correct horse", otherArgon, true, true},
+		{"argon2id under bcrypt", testBcrypt, "correct horse", argon, true, true},
+		{"bcrypt current", testBcrypt, "correct horse", bc, true, false},
+		{"bcrypt wrong password", testBcrypt, "wrong horse", bc, false, false},
+		{"bcrypt other cost", testBcrypt, "correct horse", otherBcrypt, true, true},
+		{"bcrypt under argon2id", testArgon2, "correct horse", bc, true, true},
+		{"empty hash", testArgon2, "correct horse", "", false, false},
+		{"argon2id missing part", testArgon2, "correct horse", "$argon2id$v=19$m=64,t=1,p=1$c2FsdA", false, false},
+		{"argon2id other version", testArgon2, "correct horse", strings.Replace(argon, "v=19", "v=16", 1), false, false},
+		{"argon2id bad salt", testArgon2, "correct horse", strings.Replace(argon, "p=1$", "p=1$!", 1), false, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			configure(t, tc.current)
+			ok, outdated := CheckPassword(tc.password, tc.hash)
+			if ok != tc.wantOK || outdated != tc.wantOutdated {
+				t.Errorf("CheckPassword = %v, %v, want %v, %v", ok, outdated, tc.wantOK, tc.wantOutdated)
+			}
+		})
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  Params
+		wantErr bool
+	}{
+		{"argon2id", testArgon2, false},
+		{"bcrypt", testBcrypt, false},
+		{"argon2id no parallelism", Params{Algorithm: AlgorithmArgon2id, Memory: 64, Iterations: 1}, true},
+		{"argon2id no iterations", Params{Algorithm: AlgorithmArgon2id, Memory: 64, Parallelism: 1}, true},
+		{"argon2id too little memory", Params{Algorithm: AlgorithmArgon2id, Memory: 8, Iterations: 1, Parallelism: 2}, true},
+		{"argon2id too much memory", Params{Algorithm: AlgorithmArgon2id, Memory: argon2MaxMemory + 1, Iterations: 1, Parallelism: 1}, true},
+		{"bcrypt cost too low", Params{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MinCost - 1}, true},
+		{"bcrypt cost too high", Params{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MaxCost + 1}, true},
+		{"unknown algorithm", Params{Algorithm: "md5"}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() { _ = Configure(DefaultParams) })
+			if err := Configure(tc.params); (err != nil) != tc.wantErr {
+				t.Errorf("Configure error = %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxBytes is the most of a password bcrypt looks at. Anything longer would
+// be cut off silently, so such passwords are rejected instead.
+const MaxBytes = 72
+
+// minPersonalLength keeps very short names from ruling out half of all
+// passwords.
+const minPersonalLength = 3
+
+// PolicyError is returned for a password the policy does not accept. Its
+// message is meant for the user.
+type PolicyError struct {
+	Reason string
+}
+
+func (e *PolicyError) Error() string {
+	return e.Reason
+}
+
+func violation(format string, args ...interface{}) error {
+	return &PolicyError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// Policy says which new passwords are acceptable. Existing passwords are not
+// re-checked; the policy applies when a password is set.
+type Policy struct {
+	MinLength int // in characters
+	MaxLength int // in bytes, capped at MaxBytes
+	// MinClasses is how many of lower case letters, upper case letters,
+	// digits and other characters the password must mix.
+	MinClasses int
+	// Breached, if set, rejects passwords known from data breaches.
+	Breached *BreachedList
+}
+
+// Validate checks the password against the policy. personal holds the email,
+// names and the like of the account; the password must not contain them.
+// Violations are reported as *PolicyError, anything else is a failure to
+// read the breached-password list.
+func (p *Policy) Validate(password string, personal ...string) error {
+	if n := utf8.RuneCountInString(password); n < p.MinLength {
+		return violation("password must be at least %d characters", p.MinLength)
+	}
+	if len(password) > p.maxLength() {
+		return violation("password must be at most %d bytes", p.maxLength())
+	}
+	if classes := characterClasses(password); classes < p.MinClasses {
+		return violation("password must mix at least %d of: lower case letters, upper case letters, digits, symbols", p.MinClasses)
+	}
+
+	lower := strings.ToLower(password)
+	for _, value := range personal {
+		for _, part := range personalParts(value) {
+			if strings.Contains(lower, part) {
+				return violation("password must not contain your name or email")
+			}
+		}
+	}
+
+	if p.Breached != nil {
+		breached, err := p.Breached.Contains(password)
+		if err != nil {
+			return err
+		}
+		if breached {
+			return violation("password has appeared in a data breach, choose another one")
+		}
+	}
+	return nil
+}
+
+func (p *Policy) maxLength() int {
+	if p.MaxLength <= 0 || p.MaxLength > MaxBytes {
+		return MaxBytes
+	}
+	return p.MaxLength
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+	n := 0
+	for _, ok := range []bool{lower, upper, digit, other} {
+		if ok {
+			n++
+		}
+	}
+	return n
+}
+
+// personalParts splits an email or a name into the lower-cased pieces a
+// password must not contain: the local part of an email and every word.
+func personalParts(value string) []string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if at := strings.LastIndex(value, "@"); at >= 0 {
+		value = value[:at]
+	}
+	var parts []string
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if utf8.RuneCountInString(part) >= minPersonalLength {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
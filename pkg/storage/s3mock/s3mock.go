@@ -0,0 +1,122 @@
+// Package s3mock is an in-memory stand-in for an S3-compatible service, good
+// enough for the S3 blob store during local development. Writes must be
+// signed with the configured credentials; reads are anonymous, like a public
+// bucket. Objects are lost on restart.
+package s3mock
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"news-api/pkg/storage"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const maxObjectSize = 32 << 20
+
+type object struct {
+	contentType  string
+	cacheControl string
+	data         []byte
+}
+
+type Server struct {
+	AccessKey string
+	SecretKey string
+
+	mu      sync.RWMutex
+	objects map[string]object
+}
+
+func New(accessKey, secretKey string) *Server {
+	return &Server{AccessKey: accessKey, SecretKey: secretKey, objects: map[string]object{}}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	bucket, key, _ := strings.Cut(path, "/")
+	if bucket == "" || key == "" {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", "path-style bucket/key expected")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		s.get(w, r, path)
+	case http.MethodPut:
+		s.put(w, r, path)
+	case http.MethodDelete:
+		s.delete(w, r, path)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "method not allowed")
+	}
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request, path string) {
+	s.mu.RLock()
+	obj, ok := s.objects[path]
+	s.mu.RUnlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchKey", "the specified key does not exist")
+		return
+	}
+
+	w.Header().Set("Content-Type", obj.contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+	if obj.cacheControl != "" {
+		w.Header().Set("Cache-Control", obj.cacheControl)
+	}
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(obj.data)
+	}
+}
+
+func (s *Server) put(w http.ResponseWriter, r *http.Request, path string) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxObjectSize+1))
+	if err != nil || len(body) > maxObjectSize {
+		writeError(w, http.StatusBadRequest, "EntityTooLarge", "object too large")
+		return
+	}
+	if !s.authorized(w, r, body) {
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	s.mu.Lock()
+	s.objects[path] = object{contentType: contentType, cacheControl: r.Header.Get("Cache-Control"), data: body}
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request, path string) {
+	if !s.authorized(w, r, nil) {
+		return
+	}
+	s.mu.Lock()
+	delete(s.objects, path)
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) authorized(w http.ResponseWriter, r *http.Request, body []byte) bool {
+	if err := storage.VerifyV4(r, s.AccessKey, s.SecretKey, body); err != nil {
+		writeError(w, http.StatusForbidden, "SignatureDoesNotMatch", err.Error())
+		return false
+	}
+	return true
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_ = xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string   `xml:"Code"`
+		Message string   `xml:"Message"`
+	}{Code: code, Message: message})
+}
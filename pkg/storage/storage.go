@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"news-api/internal/config"
+	"strings"
+)
+
+// BlobStore keeps uploaded files. Keys are slash separated paths such as
+// "avatars/12/3f9c_large.jpg"; URL returns the public address of a key,
+// which does not change as long as the object exists.
+type BlobStore interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// New builds the store selected by STORAGE_DRIVER: "local" or "s3".
+func New(cfg config.StorageConfig) (BlobStore, error) {
+	switch cfg.Driver {
+	case "local", "":
+		return NewLocalStore(cfg.Dir, cfg.PublicURL)
+	case "s3":
+		return NewS3Store(S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PublicURL: cfg.PublicURL,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+// checkKey rejects keys that could escape the store, such as "../x" or
+// "/etc/passwd".
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+// Package textdiff computes line diffs with the Myers algorithm, the one
+// behind diff(1) and git, so that the result is a shortest edit script.
+package textdiff
+
+import "strings"
+
+type Op string
+
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+// Edit is one line of a diff: kept, added in the new text or removed from
+// the old one.
+type Edit struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// Lines returns the edits that turn a into b, line by line.
+func Lines(a, b string) []Edit {
+	return diff(splitLines(a), splitLines(b))
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
+
+// The search below costs time and memory that grow with the number of edits,
+// so texts that differ too much, or whose changed middle is too long, are
+// reported as replaced in full instead: every old line deleted and every new
+// line inserted.
+const (
+	maxLines = 10000
+	maxEdits = 1000
+)
+
+func diff(a, b []string) []Edit {
+	// Common ends are cheap to strip and keep the search below small for the
+	// usual case of a few changed lines in a long text.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]Edit, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		edits = append(edits, Edit{Op: Equal, Text: line})
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	middle, ok := myers(midA, midB)
+	if !ok {
+		middle = replace(midA, midB)
+	}
+	edits = append(edits, middle...)
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, Edit{Op: Equal, Text: line})
+	}
+	return edits
+}
+
+func replace(a, b []string) []Edit {
+	edits := make([]Edit, 0, len(a)+len(b))
+	for _, line := range a {
+		edits = append(edits, Edit{Op: Delete, Text: line})
+	}
+	for _, line := range b {
+		edits = append(edits, Edit{Op: Insert, Text: line})
+	}
+	return edits
+}
+
+// myers finds a shortest edit script by walking diagonals of the edit graph,
+// keeping the furthest point reached for every diagonal k after d edits, and
+// then backtracks through the saved states. Only the diagonals -d..d are
+// saved for step d. It gives up, returning false, past maxLines or maxEdits.
+func myers(a, b []string) ([]Edit, bool) {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil, true
+	}
+	if n+m > maxLines {
+		return nil, false
+	}
+	max := min(n+m, maxEdits)
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	var trace [][]int
+
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace, d), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// backtrack walks back from the end of both texts. trace[d] holds the state
+// before step d for diagonals -d..d, at index k+d.
+func backtrack(a, b []string, trace [][]int, d int) []Edit {
+	var reversed []Edit
+	x, y := len(a), len(b)
+	for ; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[d+k-1] < v[d+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[d+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, Edit{Op: Equal, Text: a[x]})
+		}
+		if x == prevX {
+			y--
+			reversed = append(reversed, Edit{Op: Insert, Text: b[y]})
+		} else {
+			x--
+			reversed = append(reversed, Edit{Op: Delete, Text: a[x]})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		reversed = append(reversed, Edit{Op: Equal, Text: a[x]})
+	}
+
+	edits := make([]Edit, len(reversed))
+	for i, e := range reversed {
+		edits[len(reversed)-1-i] = e
+	}
+	return edits
+}
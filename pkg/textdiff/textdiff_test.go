@@ -0,0 +1,192 @@
+package textdiff
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// sides rebuilds the old and the new text from edits.
+func sides(edits []Edit) (string, string) {
+	var a, b []string
+	for _, e := range edits {
+		if e.Op != Insert {
+			a = append(a, e.Text)
+		}
+		if e.Op != Delete {
+			b = append(b, e.Text)
+		}
+	}
+	return strings.Join(a, "\n"), strings.Join(b, "\n")
+}
+
+func count(edits []Edit, op Op) int {
+	n := 0
+	for _, e := range edits {
+		if e.Op == op {
+			n++
+		}
+	}
+	return n
+}
+
+// lcs returns the length of the longest common subsequence, which a shortest
+// edit script keeps as Equal lines.
+func lcs(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else {
+				dp[i][j] = max(dp[i+1][j], dp[i][j+1])
+			}
+		}
+	}
+	return dp[0][0]
+}
+
+func lines(prefix string, n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = prefix + strconv.Itoa(i)
+	}
+	return out
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Edit
+	}{
+		{"both empty", "", "", []Edit{}},
+		{"insert into empty", "", "x", []Edit{{Insert, "x"}}},
+		{"delete all", "x\ny", "", []Edit{{Delete, "x"}, {Delete, "y"}}},
+		{"unchanged", "x\ny", "x\ny", []Edit{{Equal, "x"}, {Equal, "y"}}},
+		{"crlf", "x\r\ny", "x\ny", []Edit{{Equal, "x"}, {Equal, "y"}}},
+		{"change middle", "a\nb\nc", "a\nx\nc", []Edit{{Equal, "a"}, {Delete, "b"}, {Insert, "x"}, {Equal, "c"}}},
+		{"append", "a\nb", "a\nb\nc", []Edit{{Equal, "a"}, {Equal, "b"}, {Insert, "c"}}},
+		{"prepend", "b\nc", "a\nb\nc", []Edit{{Insert, "a"}, {Equal, "b"}, {Equal, "c"}}},
+		{"move line", "a\nb\nc", "b\nc\na", []Edit{{Delete, "a"}, {Equal, "b"}, {Equal, "c"}, {Insert, "a"}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Lines(tc.a, tc.b)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Lines = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLinesRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	text := func() []string {
+		out := make([]string, r.Intn(12))
+		for i := range out {
+			out[i] = string(rune('a' + r.Intn(4)))
+		}
+		return out
+	}
+
+	for i := 0; i < 5000; i++ {
+		a, b := text(), text()
+		edits := Lines(strings.Join(a, "\n"), strings.Join(b, "\n"))
+
+		gotA, gotB := sides(edits)
+		if gotA != strings.Join(a, "\n") || gotB != strings.Join(b, "\n") {
+			t.Fatalf("Lines(%q, %q) = %v does not rebuild both texts", a, b, edits)
+		}
+		if eq := count(edits, Equal); eq != lcs(a, b) {
+			t.Fatalf("Lines(%q, %q) keeps %d lines, want %d", a, b, eq, lcs(a, b))
+		}
+	}
+}
+
+// TestLinesMaxLines checks the cut-off on the size of the changed middle:
+// the first and the last line differ, so no common ends are stripped.
+func TestLinesMaxLines(t *testing.T) {
+	tests := []struct {
+		name      string
+		n         int
+		wantEqual int
+	}{
+		{"at the limit", maxLines / 2, maxLines/2 - 2},
+		{"over the limit", maxLines/2 + 1, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a := lines("line ", tc.n)
+			b := append([]string(nil), a...)
+			b[0], b[len(b)-1] = "first", "last"
+
+			edits := Lines(strings.Join(a, "\n"), strings.Join(b, "\n"))
+			gotA, gotB := sides(edits)
+			if gotA != strings.Join(a, "\n") || gotB != strings.Join(b, "\n") {
+				t.Fatal("edits do not rebuild both texts")
+			}
+			if eq := count(edits, Equal); eq != tc.wantEqual {
+				t.Errorf("kept %d lines, want %d", eq, tc.wantEqual)
+			}
+		})
+	}
+}
+
+// TestLinesMaxEdits checks the cut-off on the number of edits: one common
+// line sits between texts that differ everywhere else.
+func TestLinesMaxEdits(t *testing.T) {
+	tests := []struct {
+		name      string
+		extra     int
+		wantEqual int
+	}{
+		{"at the limit", 0, 1},
+		{"over the limit", 1, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			half := maxEdits / 4
+			a := append(append(lines("old ", half), "common"), lines("old tail ", half+tc.extra)...)
+			b := append(append(lines("new ", half), "common"), lines("new tail ", half)...)
+
+			edits := Lines(strings.Join(a, "\n"), strings.Join(b, "\n"))
+			gotA, gotB := sides(edits)
+			if gotA != strings.Join(a, "\n") || gotB != strings.Join(b, "\n") {
+				t.Fatal("edits do not rebuild both texts")
+			}
+			if eq := count(edits, Equal); eq != tc.wantEqual {
+				t.Errorf("kept %d lines, want %d", eq, tc.wantEqual)
+			}
+		})
+	}
+}
+
+// TestLinesFallbackKeepsEnds checks that a full replace still keeps the
+// common first and last lines.
+func TestLinesFallbackKeepsEnds(t *testing.T) {
+	a := append(append([]string{"head"}, lines("old ", maxEdits)...), "tail")
+	b := append(append([]string{"head"}, lines("new ", maxEdits)...), "tail")
+
+	edits := Lines(strings.Join(a, "\n"), strings.Join(b, "\n"))
+	if len(edits) != 2*maxEdits+2 {
+		t.Fatalf("got %d edits, want %d", len(edits), 2*maxEdits+2)
+	}
+	if edits[0] != (Edit{Equal, "head"}) || edits[len(edits)-1] != (Edit{Equal, "tail"}) {
+		t.Errorf("ends not kept: %v ... %v", edits[0], edits[len(edits)-1])
+	}
+	for i, e := range edits[1 : len(edits)-1] {
+		want := Delete
+		if i >= maxEdits {
+			want = Insert
+		}
+		if e.Op != want {
+			t.Fatalf("edit %d is %s, want every old line deleted before the new ones are inserted", i+1, e.Op)
+		}
+	}
+}
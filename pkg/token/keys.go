@@ -0,0 +1,232 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+
+	rsaKeyBits = 2048
+)
+
+// Key is an asymmetric signing key. A key with a zero ExpiresAt is active;
+// once rotated out it only verifies tokens until ExpiresAt. An active key
+// verifies tokens from the start but signs only from ActivatesAt, so that
+// other replicas and JWKS consumers learn it before they see its tokens.
+type Key struct {
+	ID          string
+	Algorithm   string
+	CreatedAt   time.Time
+	ActivatesAt time.Time
+	ExpiresAt   time.Time
+
+	private crypto.Signer
+}
+
+func GenerateKey(algorithm string) (*Key, error) {
+	id, err := NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	var private crypto.Signer
+	switch algorithm {
+	case AlgRS256:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case AlgEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Key{ID: id, Algorithm: algorithm, CreatedAt: time.Now().UTC(), private: private}, nil
+}
+
+// ParseKey restores a key from the PKCS#8 PEM produced by MarshalPrivateKey.
+func ParseKey(id, algorithm string, privatePEM []byte, createdAt, expiresAt time.Time) (*Key, error) {
+	block, _ := pem.Decode(privatePEM)
+	if block == nil {
+		return nil, errors.New("invalid private key PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key type")
+	}
+	switch private.(type) {
+	case *rsa.PrivateKey:
+		if algorithm != AlgRS256 {
+			return nil, fmt.Errorf("key %s: RSA key used with %s", id, algorithm)
+		}
+	case ed25519.PrivateKey:
+		if algorithm != AlgEdDSA {
+			return nil, fmt.Errorf("key %s: Ed25519 key used with %s", id, algorithm)
+		}
+	default:
+		return nil, errors.New("unsupported private key type")
+	}
+
+	return &Key{ID: id, Algorithm: algorithm, CreatedAt: createdAt, ExpiresAt: expiresAt, private: private}, nil
+}
+
+func (k *Key) MarshalPrivateKey() ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(k.private)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+func (k *Key) Active() bool {
+	return k.ExpiresAt.IsZero()
+}
+
+// Signing reports whether the key may sign tokens at the given time.
+func (k *Key) Signing(now time.Time) bool {
+	return k.Active() && !now.Before(k.ActivatesAt)
+}
+
+// activation is when the key started or starts signing.
+func (k *Key) activation() time.Time {
+	if k.ActivatesAt.IsZero() {
+		return k.CreatedAt
+	}
+	return k.ActivatesAt
+}
+
+func (k *Key) Expired(now time.Time) bool {
+	return !k.Active() && now.After(k.ExpiresAt)
+}
+
+func (k *Key) method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+// JWK is the public part of a key as published in the JWKS document.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func (k *Key) JWK() JWK {
+	jwk := JWK{Kid: k.ID, Use: "sig", Alg: k.Algorithm}
+	switch pub := k.private.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+	return jwk
+}
+
+// KeyRing holds the keys currently known to this process. The newest active
+// key signs new tokens; every unexpired key verifies them.
+type KeyRing struct {
+	mu   sync.RWMutex
+	keys []*Key
+}
+
+func NewKeyRing() *KeyRing {
+	return &KeyRing{}
+}
+
+// Set replaces the contents of the ring.
+func (r *KeyRing) Set(keys []*Key) {
+	sorted := sortKeys(keys)
+
+	r.mu.Lock()
+	r.keys = sorted
+	r.mu.Unlock()
+}
+
+// Current returns the signing key, or nil if the ring has no key that may
+// sign yet.
+func (r *KeyRing) Current() *Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return signingKey(r.keys, time.Now())
+}
+
+// signingKey returns the newest key of keys, sorted by creation, that may
+// sign at the given time.
+func signingKey(keys []*Key, now time.Time) *Key {
+	for i := len(keys) - 1; i >= 0; i-- {
+		if keys[i].Signing(now) {
+			return keys[i]
+		}
+	}
+	return nil
+}
+
+// Lookup returns the key with the given id if it may still verify tokens.
+func (r *KeyRing) Lookup(kid string) *Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	for _, k := range r.keys {
+		if k.ID == kid && !k.Expired(now) {
+			return k
+		}
+	}
+	return nil
+}
+
+func (r *KeyRing) JWKS() JWKS {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	set := JWKS{Keys: []JWK{}}
+	for _, k := range r.keys {
+		if !k.Expired(now) {
+			set.Keys = append(set.Keys, k.JWK())
+		}
+	}
+	return set
+}
+
+func sortKeys(keys []*Key) []*Key {
+	sorted := append([]*Key(nil), keys...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].CreatedAt.Before(sorted[j].CreatedAt) })
+	return sorted
+}
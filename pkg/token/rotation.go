@@ -0,0 +1,177 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"news-api/pkg/logger"
+)
+
+const rotationLockTTL = 30 * time.Second
+
+// ErrKeyUnreadable is returned by LoadKeys for a stored key that cannot be
+// decoded or decrypted. Carrying on without it would make the replica
+// rotate to a key the others may not be able to read.
+var ErrKeyUnreadable = errors.New("stored signing key cannot be read")
+
+// KeyStore persists signing keys so that every replica signs and verifies
+// with the same key set. AcquireRotationLock returns an empty lock when
+// another replica holds it; ReleaseRotationLock only releases the lock it is
+// given.
+type KeyStore interface {
+	LoadKeys(ctx context.Context) ([]*Key, error)
+	SaveKey(ctx context.Context, key *Key) error
+	DeleteKey(ctx context.Context, kid string) error
+	AcquireRotationLock(ctx context.Context, ttl time.Duration) (string, error)
+	ReleaseRotationLock(ctx context.Context, lock string) error
+}
+
+// KeyRotator keeps a KeyRing in sync with the KeyStore and replaces the
+// signing key once it is older than the rotation interval. A new key is
+// published lead ahead of signing with it, so that every replica has loaded
+// it and JWKS caches have picked it up before its first token is seen.
+// Retired keys keep verifying for the retention period so that tokens signed
+// with them remain valid until they expire.
+type KeyRotator struct {
+	store     KeyStore
+	ring      *KeyRing
+	algorithm string
+	interval  time.Duration
+	lead      time.Duration
+	retention time.Duration
+}
+
+func NewKeyRotator(store KeyStore, ring *KeyRing, algorithm string, interval, lead, retention time.Duration) *KeyRotator {
+	return &KeyRotator{store: store, ring: ring, algorithm: algorithm, interval: interval, lead: lead, retention: retention}
+}
+
+// Sync loads the keys from the store, drops expired ones, publishes the next
+// signing key when rotation is due and retires the previous one once the
+// next has taken over.
+func (r *KeyRotator) Sync(ctx context.Context) error {
+	keys, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	if now := time.Now(); r.rotationDue(keys, now) || r.retirementDue(keys, now) {
+		lock, err := r.store.AcquireRotationLock(ctx, rotationLockTTL)
+		if err != nil {
+			return err
+		}
+		if lock != "" {
+			keys, err = r.rotate(ctx)
+			_ = r.store.ReleaseRotationLock(ctx, lock)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	r.ring.Set(keys)
+	return nil
+}
+
+// Run calls Sync periodically until ctx is cancelled.
+func (r *KeyRotator) Run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Sync(ctx); err != nil {
+				logger.Log.Error("Signing key sync failed", "error", err)
+			}
+		}
+	}
+}
+
+func (r *KeyRotator) load(ctx context.Context) ([]*Key, error) {
+	stored, err := r.store.LoadKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	keys := make([]*Key, 0, len(stored))
+	for _, k := range stored {
+		if k.Expired(now) {
+			if err := r.store.DeleteKey(ctx, k.ID); err != nil {
+				return nil, err
+			}
+			logger.Log.Info("Signing key expired", "kid", k.ID)
+			continue
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// rotate runs under the rotation lock. It reloads the keys in case another
+// replica rotated in the meantime.
+func (r *KeyRotator) rotate(ctx context.Context) ([]*Key, error) {
+	keys, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if r.rotationDue(keys, now) {
+		next, err := GenerateKey(r.algorithm)
+		if err != nil {
+			return nil, err
+		}
+		// With no key to sign in the meantime the first one starts at once.
+		if signingKey(sortKeys(keys), now) != nil {
+			next.ActivatesAt = next.CreatedAt.Add(r.lead)
+		}
+		if err := r.store.SaveKey(ctx, next); err != nil {
+			return nil, err
+		}
+		logger.Log.Info("Signing key published", "kid", next.ID, "alg", next.Algorithm, "activates_at", next.ActivatesAt)
+		keys = append(keys, next)
+	}
+
+	current := signingKey(sortKeys(keys), now)
+	for _, k := range keys {
+		if k != current && k.Signing(now) {
+			k.ExpiresAt = current.activation().Add(r.retention)
+			if err := r.store.SaveKey(ctx, k); err != nil {
+				return nil, err
+			}
+			logger.Log.Info("Signing key retired", "kid", k.ID, "expires_at", k.ExpiresAt)
+		}
+	}
+	return keys, nil
+}
+
+// rotationDue reports whether the newest active key, published or already
+// signing, is older than the interval or uses another algorithm.
+func (r *KeyRotator) rotationDue(keys []*Key, now time.Time) bool {
+	var newest *Key
+	for _, k := range keys {
+		if k.Active() && (newest == nil || k.CreatedAt.After(newest.CreatedAt)) {
+			newest = k
+		}
+	}
+	if newest == nil {
+		return true
+	}
+	return newest.Algorithm != r.algorithm || now.Sub(newest.activation()) >= r.interval
+}
+
+// retirementDue reports whether a key other than the current one may still
+// sign, which is the case once a published key has taken over.
+func (r *KeyRotator) retirementDue(keys []*Key, now time.Time) bool {
+	current := signingKey(sortKeys(keys), now)
+	for _, k := range keys {
+		if k != current && k.Signing(now) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,41 +1,105 @@
 package token
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	TypeAccess    = "access"
+	TypeRefresh   = "refresh"
+	TypeMagicLink = "magic_link"
+
+	RefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+// JWTManager signs tokens either with a shared HS256 secret or, when Keys is
+// set, with the current asymmetric key of the ring identified by "kid".
 type JWTManager struct {
 	Secret          string
 	ExpirationHours int
+	Keys            *KeyRing
 }
 
 func NewJWTManager(secret string, expirationHours int) *JWTManager {
 	return &JWTManager{Secret: secret, ExpirationHours: expirationHours}
 }
 
-func (j *JWTManager) GenerateTokens(userID int, role string) (accessToken string, refreshToken string, err error) {
-	now := time.Now()
+func NewKeyRingJWTManager(keys *KeyRing, expirationHours int) *JWTManager {
+	return &JWTManager{Keys: keys, ExpirationHours: expirationHours}
+}
 
-	accessClaims := jwt.MapClaims{
-		"user_id": userID,
-		"role":    role,
-		"exp":     now.Add(time.Duration(j.ExpirationHours) * time.Hour).Unix(),
+// NewID returns a random identifier suitable for jti and session id claims.
+func NewID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
-	access := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessToken, err = access.SignedString([]byte(j.Secret))
+	return hex.EncodeToString(b), nil
+}
+
+// NewSecret returns a random opaque token for links sent to users together
+// with its hash. Only the hash is meant to be stored.
+func NewSecret() (plain string, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	plain = base64.RawURLEncoding.EncodeToString(b)
+	return plain, HashSecret(plain), nil
+}
+
+func HashSecret(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+func (j *JWTManager) AccessTokenTTL() time.Duration {
+	return time.Duration(j.ExpirationHours) * time.Hour
+}
+
+// Identity is what an access token states about its holder.
+type Identity struct {
+	UserID        int
+	Role          string
+	SessionID     string
+	EmailVerified bool
+	// ImpersonatorID is the admin acting as the user, if any. It is carried
+	// in the "act" claim (RFC 8693).
+	ImpersonatorID int
+}
+
+// GenerateTokens issues an access/refresh pair bound to a session. Every
+// rotation of the refresh token keeps the session id of the original login.
+func (j *JWTManager) GenerateTokens(id Identity) (accessToken string, refreshToken string, err error) {
+	now := time.Now()
+
+	accessToken, _, err = j.accessToken(id, now, j.AccessTokenTTL())
 	if err != nil {
 		return "", "", err
 	}
 
+	refreshID, err := NewID()
+	if err != nil {
+		return "", "", err
+	}
 	refreshClaims := jwt.MapClaims{
-		"user_id": userID,
-		"exp":     now.Add(7 * 24 * time.Hour).Unix(),
+		"user_id": id.UserID,
+		"typ":     TypeRefresh,
+		"sid":     id.SessionID,
+		"jti":     refreshID,
+		"iat":     issuedAt(now),
+		"exp":     now.Add(RefreshTokenTTL).Unix(),
 	}
-	refresh := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshToken, err = refresh.SignedString([]byte(j.Secret))
+	refreshToken, err = j.sign(refreshClaims)
 	if err != nil {
 		return "", "", err
 	}
@@ -43,13 +107,163 @@ func (j *JWTManager) GenerateTokens(userID int, role string) (accessToken string
 	return accessToken, refreshToken, nil
 }
 
+// GenerateAccessToken issues a lone access token with the given lifetime and
+// returns it with its jti. There is no refresh token to extend it.
+func (j *JWTManager) GenerateAccessToken(id Identity, ttl time.Duration) (accessToken string, tokenID string, err error) {
+	return j.accessToken(id, time.Now(), ttl)
+}
+
+func (j *JWTManager) accessToken(id Identity, now time.Time, ttl time.Duration) (string, string, error) {
+	accessID, err := NewID()
+	if err != nil {
+		return "", "", err
+	}
+	claims := jwt.MapClaims{
+		"user_id":        id.UserID,
+		"role":           id.Role,
+		"sid":            id.SessionID,
+		"email_verified": id.EmailVerified,
+		"typ":            TypeAccess,
+		"jti":            accessID,
+		"iat":            issuedAt(now),
+		"exp":            now.Add(ttl).Unix(),
+	}
+	if id.ImpersonatorID != 0 {
+		claims["act"] = map[string]interface{}{"sub": strconv.Itoa(id.ImpersonatorID)}
+	}
+	signed, err := j.sign(claims)
+	if err != nil {
+		return "", "", err
+	}
+	return signed, accessID, nil
+}
+
+// issuedAt encodes the "iat" claim with microseconds, so that a token issued
+// right after its user's tokens were revoked is told apart from the revoked
+// ones issued in the same second.
+func issuedAt(t time.Time) float64 {
+	return float64(t.UnixMicro()) / 1e6
+}
+
+// IssuedAt returns the "iat" claim of validated token claims. Tokens issued
+// before it carried fractions of a second read as the start of their second.
+func IssuedAt(claims map[string]interface{}) time.Time {
+	iat, _ := claims["iat"].(float64)
+	return time.UnixMicro(int64(math.Round(iat * 1e6)))
+}
+
+// ImpersonatorID returns the admin named in the "act" claim of validated
+// access token claims, or 0 if the token is not an impersonation token.
+func ImpersonatorID(claims map[string]interface{}) int {
+	act, _ := claims["act"].(map[string]interface{})
+	sub, _ := act["sub"].(string)
+	id, err := strconv.Atoi(sub)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// ValidateToken validates an access token. Tokens of any other type are
+// rejected.
 func (j *JWTManager) ValidateToken(tokenString string) (map[string]interface{}, error) {
-	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
+	claims, err := j.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if typ, _ := claims["typ"].(string); typ != TypeAccess {
+		return nil, errors.New("unexpected token type")
+	}
+	return claims, nil
+}
+
+// ValidateRefreshToken validates a refresh token. Access tokens are rejected.
+func (j *JWTManager) ValidateRefreshToken(tokenString string) (map[string]interface{}, error) {
+	claims, err := j.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if typ, _ := claims["typ"].(string); typ != TypeRefresh {
+		return nil, errors.New("unexpected token type")
+	}
+	return claims, nil
+}
+
+// GenerateMagicLinkToken signs the token of a passwordless login link. The
+// nonce is its jti; the caller stores it to make the link single-use.
+func (j *JWTManager) GenerateMagicLinkToken(userID int, nonce string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	return j.sign(jwt.MapClaims{
+		"user_id": userID,
+		"typ":     TypeMagicLink,
+		"jti":     nonce,
+		"iat":     issuedAt(now),
+		"exp":     now.Add(ttl).Unix(),
+	})
+}
+
+// ValidateMagicLinkToken checks the signature and expiry of a magic link
+// token and returns the user and nonce it was issued for.
+func (j *JWTManager) ValidateMagicLinkToken(tokenString string) (userID int, nonce string, err error) {
+	claims, err := j.parse(tokenString)
+	if err != nil {
+		return 0, "", err
+	}
+	if typ, _ := claims["typ"].(string); typ != TypeMagicLink {
+		return 0, "", errors.New("unexpected token type")
+	}
+	uid, _ := claims["user_id"].(float64)
+	nonce, _ = claims["jti"].(string)
+	if uid <= 0 || nonce == "" {
+		return 0, "", errors.New("invalid token payload")
+	}
+	return int(uid), nonce, nil
+}
+
+// JWKS returns the public keys that verify tokens issued by this manager.
+// It is empty in HS256 mode since a shared secret cannot be published.
+func (j *JWTManager) JWKS() JWKS {
+	if j.Keys == nil {
+		return JWKS{Keys: []JWK{}}
+	}
+	return j.Keys.JWKS()
+}
+
+func (j *JWTManager) sign(claims jwt.MapClaims) (string, error) {
+	if j.Keys == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(j.Secret))
+	}
+
+	key := j.Keys.Current()
+	if key == nil {
+		return "", errors.New("no active signing key")
+	}
+	t := jwt.NewWithClaims(key.method(), claims)
+	t.Header["kid"] = key.ID
+	return t.SignedString(key.private)
+}
+
+func (j *JWTManager) verificationKey(t *jwt.Token) (interface{}, error) {
+	if j.Keys == nil {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
 		return []byte(j.Secret), nil
-	})
+	}
+
+	kid, _ := t.Header["kid"].(string)
+	key := j.Keys.Lookup(kid)
+	if key == nil {
+		return nil, errors.New("unknown signing key")
+	}
+	if t.Method.Alg() != key.Algorithm {
+		return nil, errors.New("unexpected signing method")
+	}
+	return key.private.Public(), nil
+}
+
+func (j *JWTManager) parse(tokenString string) (map[string]interface{}, error) {
+	token, err := jwt.Parse(tokenString, j.verificationKey)
 	if err != nil {
 		return nil, err
 	}
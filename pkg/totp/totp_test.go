@@ -0,0 +1,103 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 seed of RFC 6238 appendix B, "12345678901234567890",
+// in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// rfcVectors are the SHA1 test values of RFC 6238 appendix B, cut to the
+// last six digits.
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestCode(t *testing.T) {
+	for _, tc := range rfcVectors {
+		got, err := Code(rfcSecret, Step(time.Unix(tc.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code at %d: %v", tc.unix, err)
+		}
+		if got != tc.code {
+			t.Errorf("Code at %d = %s, want %s", tc.unix, got, tc.code)
+		}
+	}
+}
+
+func TestCodeLowercaseSecret(t *testing.T) {
+	got, err := Code(" gezdgnbvgy3tqojqgezdgnbvgy3tqojq ", Step(time.Unix(59, 0)))
+	if err != nil || got != "287082" {
+		t.Errorf("Code = %q, %v, want 287082", got, err)
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Error("Code accepted an invalid secret")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	at := time.Unix(1111111111, 0)
+	step := Step(at)
+
+	tests := []struct {
+		name     string
+		code     string
+		at       time.Time
+		skew     int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current step", "050471", at, 0, step, true},
+		{"with spaces", " 050 471 ", at, 0, step, true},
+		{"previous step within skew", "050471", at.Add(Period * time.Second), 1, step, true},
+		{"next step within skew", "050471", at.Add(-Period * time.Second), 1, step, true},
+		{"outside skew", "050471", at.Add(2 * Period * time.Second), 1, 0, false},
+		{"no skew", "050471", at.Add(Period * time.Second), 0, 0, false},
+		{"wrong code", "000000", at, 1, 0, false},
+		{"too short", "05047", at, 1, 0, false},
+		{"too long", "0504711", at, 1, 0, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			gotStep, ok := Validate(rfcSecret, tc.code, tc.at, tc.skew)
+			if ok != tc.wantOK || gotStep != tc.wantStep {
+				t.Errorf("Validate = %d, %v, want %d, %v", gotStep, ok, tc.wantStep, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Error("GenerateSecret returned the same secret twice")
+	}
+
+	code, err := Code(a, 1)
+	if err != nil {
+		t.Fatalf("Code with generated secret: %v", err)
+	}
+	if _, ok := Validate(a, code, time.Unix(Period, 0), 0); !ok {
+		t.Error("code from a generated secret does not validate")
+	}
+}
@@ -0,0 +1,16 @@
+package utils
+
+import (
+	"net"
+	"net/http"
+	"news-api/internal/models"
+)
+
+// ClientInfo extracts the caller's address and user agent from the request.
+func ClientInfo(r *http.Request) models.ClientInfo {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return models.ClientInfo{IP: ip, UserAgent: r.UserAgent()}
+}
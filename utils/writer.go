@@ -2,8 +2,11 @@ package utils
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
 	"news-api/internal/dto/errors"
+	"strconv"
+	"time"
 )
 
 func WriteError(w http.ResponseWriter, code int, message string) {
@@ -23,3 +26,8 @@ func WriteJSON(w http.ResponseWriter, code int, payload interface{}) {
 	w.WriteHeader(code)
 	_ = json.NewEncoder(w).Encode(payload)
 }
+
+// SetRetryAfter sets the Retry-After header in whole seconds, rounding up.
+func SetRetryAfter(w http.ResponseWriter, d time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+}